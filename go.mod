@@ -1,6 +1,6 @@
 module github.com/krzysztofreczek/go-structurizr
 
-go 1.18
+go 1.22.0
 
 require (
 	github.com/cnf/structhash v0.0.0-20250313080605-df4c6cc74a9a
@@ -12,4 +12,10 @@ require (
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require github.com/pmezard/go-difflib v1.0.0 // indirect
+require golang.org/x/tools v0.26.0
+
+require (
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
@@ -0,0 +1,176 @@
+// Package arch provides rules asserting the architecture of scraped structures.
+//
+// Rules are checked against a `model.Structure`, e.g. in tests:
+//
+//	err := arch.Check(structure,
+//	    arch.NoDependency("HANDLER", "REPOSITORY"),
+//	    arch.NoCycles(),
+//	)
+//	require.NoError(t, err)
+package arch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+// Rule defines an architecture rule that can be checked against a structure.
+//
+// String returns a human-readable description of the rule.
+// Check returns the violations of the rule found in the structure.
+type Rule interface {
+	String() string
+	Check(s model.Structure) []Violation
+}
+
+type rule struct {
+	description string
+	check       func(description string, s model.Structure) []Violation
+}
+
+func newRule(
+	description string,
+	check func(description string, s model.Structure) []Violation,
+) Rule {
+	return rule{
+		description: description,
+		check:       check,
+	}
+}
+
+func (r rule) String() string {
+	return r.description
+}
+
+func (r rule) Check(s model.Structure) []Violation {
+	return r.check(r.description, s)
+}
+
+// Violation describes a single violation of a rule.
+//
+// Rule is the description of the violated rule.
+// Components contains the offending components.
+// Relations contains the offending relations, if the rule concerns relations.
+type Violation struct {
+	Rule       string
+	Components []model.Component
+	Relations  []model.Relation
+}
+
+func (v Violation) Error() string {
+	details := make([]string, 0, len(v.Components)+len(v.Relations))
+
+	if len(v.Relations) == 0 {
+		for _, c := range v.Components {
+			details = append(details, componentString(c))
+		}
+	}
+
+	names := make(map[string]string, len(v.Components))
+	for _, c := range v.Components {
+		names[c.ID] = componentString(c)
+	}
+	for _, r := range v.Relations {
+		details = append(details, relationString(r, names))
+	}
+
+	return fmt.Sprintf("%s: %s", v.Rule, strings.Join(details, ", "))
+}
+
+// Violations aggregates violations of all the checked rules.
+type Violations []Violation
+
+func (v Violations) Error() string {
+	lines := make([]string, 0, len(v)+1)
+	lines = append(lines, fmt.Sprintf("%d architecture rule violation(s):", len(v)))
+	for _, violation := range v {
+		lines = append(lines, "  - "+violation.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the aggregated violations as errors.
+func (v Violations) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, violation := range v {
+		errs[i] = violation
+	}
+	return errs
+}
+
+// Check checks the rules against the structure.
+//
+// It returns Violations if any of the rules is violated, nil otherwise.
+func Check(s model.Structure, rules ...Rule) error {
+	violations := make(Violations, 0)
+	for _, r := range rules {
+		violations = append(violations, r.Check(s)...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+func componentString(c model.Component) string {
+	return fmt.Sprintf("`%s` (%s)", c.Name, c.ID)
+}
+
+func relationString(r model.Relation, names map[string]string) string {
+	s := fmt.Sprintf("%s -> %s", names[r.SourceID], names[r.TargetID])
+	if r.Path != "" {
+		s += fmt.Sprintf(" via `%s`", r.Path)
+	}
+	return s
+}
+
+func componentViolation(rule string, c model.Component) Violation {
+	return Violation{
+		Rule:       rule,
+		Components: []model.Component{c},
+	}
+}
+
+func relationViolation(rule string, s model.Structure, relations ...model.Relation) Violation {
+	return Violation{
+		Rule:       rule,
+		Components: relationComponents(s, relations),
+		Relations:  relations,
+	}
+}
+
+// relationComponents returns the components connected by the relations,
+// in the order they are reached.
+func relationComponents(s model.Structure, relations []model.Relation) []model.Component {
+	components := make([]model.Component, 0)
+	seen := make(map[string]struct{})
+	for _, r := range relations {
+		for _, id := range []string{r.SourceID, r.TargetID} {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			components = append(components, s.Components[id])
+		}
+	}
+	return components
+}
+
+// matchesTags checks whether the component is tagged with any of the tags.
+// All components match if no tags are given.
+func matchesTags(c model.Component, tags ...string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, t := range tags {
+		for _, ct := range c.Tags {
+			if t == ct {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package arch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/graph"
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+// NoDependency returns a rule asserting that components tagged with
+// the source tag do not depend directly on components tagged with
+// the target tag, e.g. `NoDependency("HANDLER", "REPOSITORY")`.
+//
+// Each offending relation is reported as a separate violation.
+func NoDependency(sourceTag string, targetTag string) Rule {
+	description := fmt.Sprintf(
+		"components tagged `%s` must not depend on components tagged `%s`",
+		sourceTag, targetTag)
+
+	return newRule(description, func(description string, s model.Structure) []Violation {
+		g := graph.NewGraph(s)
+
+		violations := make([]Violation, 0)
+		for _, id := range g.Components() {
+			if !matchesTags(s.Components[id], sourceTag) {
+				continue
+			}
+			for _, r := range g.Relations(id) {
+				if matchesTags(s.Components[r.TargetID], targetTag) {
+					violations = append(violations, relationViolation(description, s, r))
+				}
+			}
+		}
+		return violations
+	})
+}
+
+// NoCycles returns a rule asserting that there are no cycles
+// of relations between components.
+//
+// Each group of components forming cycles is reported as a separate
+// violation, together with the relations between them.
+func NoCycles() Rule {
+	return newRule("components must not form cycles", func(description string, s model.Structure) []Violation {
+		g := graph.NewGraph(s)
+
+		violations := make([]Violation, 0)
+		for _, ids := range g.Cycles() {
+			group := make(map[string]struct{}, len(ids))
+			for _, id := range ids {
+				group[id] = struct{}{}
+			}
+
+			relations := make([]model.Relation, 0)
+			for _, id := range ids {
+				for _, r := range g.Relations(id) {
+					if _, ok := group[r.TargetID]; ok {
+						relations = append(relations, r)
+					}
+				}
+			}
+
+			violations = append(violations, relationViolation(description, s, relations...))
+		}
+		return violations
+	})
+}
+
+// RequireDescription returns a rule asserting that components have
+// a description.
+//
+// If any tags are given, only the components tagged with at least one
+// of them are checked.
+func RequireDescription(tags ...string) Rule {
+	description := "components" + tagsDescription(tags) + " must have a description"
+
+	return newRule(description, func(description string, s model.Structure) []Violation {
+		violations := make([]Violation, 0)
+		for _, id := range graph.NewGraph(s).Components() {
+			c := s.Components[id]
+			if matchesTags(c, tags...) && strings.TrimSpace(c.Description) == "" {
+				violations = append(violations, componentViolation(description, c))
+			}
+		}
+		return violations
+	})
+}
+
+// MaxOutgoingRelations returns a rule asserting that components have
+// at most n outgoing relations.
+//
+// If any tags are given, only the components tagged with at least one
+// of them are checked.
+func MaxOutgoingRelations(n int, tags ...string) Rule {
+	description := fmt.Sprintf("components%s must have at most %d outgoing relations", tagsDescription(tags), n)
+
+	return newRule(description, func(description string, s model.Structure) []Violation {
+		g := graph.NewGraph(s)
+
+		violations := make([]Violation, 0)
+		for _, id := range g.Components() {
+			if !matchesTags(s.Components[id], tags...) {
+				continue
+			}
+			relations := g.Relations(id)
+			if len(relations) > n {
+				violations = append(violations, relationViolation(description, s, relations...))
+			}
+		}
+		return violations
+	})
+}
+
+func tagsDescription(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" tagged `%s`", strings.Join(tags, "` or `"))
+}
@@ -0,0 +1,126 @@
+package arch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/arch"
+	"github.com/krzysztofreczek/go-structurizr/pkg/internal/test"
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/scraper"
+	"github.com/stretchr/testify/require"
+)
+
+func testStructure() model.Structure {
+	s := model.NewStructure()
+	s.AddComponent(model.Component{ID: "H", Name: "Handler", Description: "handles", Tags: []string{"HANDLER"}}, "")
+	s.AddComponent(model.Component{ID: "S", Name: "Service", Description: "serves", Tags: []string{"SERVICE"}}, "H")
+	s.AddComponent(model.Component{ID: "R", Name: "Repository", Tags: []string{"REPOSITORY"}}, "S")
+	s.AddRelation(model.Relation{SourceID: "H", TargetID: "R", Path: "Handler.repository"})
+	return s
+}
+
+func TestNoDependency(t *testing.T) {
+	s := testStructure()
+
+	violations := arch.NoDependency("HANDLER", "REPOSITORY").Check(s)
+	require.Len(t, violations, 1)
+	require.Equal(t, []model.Relation{
+		{SourceID: "H", TargetID: "R", Path: "Handler.repository"},
+	}, violations[0].Relations)
+	require.Equal(t, []model.Component{s.Components["H"], s.Components["R"]}, violations[0].Components)
+	require.Equal(t,
+		"components tagged `HANDLER` must not depend on components tagged `REPOSITORY`: "+
+			"`Handler` (H) -> `Repository` (R) via `Handler.repository`",
+		violations[0].Error())
+
+	require.Empty(t, arch.NoDependency("REPOSITORY", "HANDLER").Check(s))
+}
+
+func TestNoCycles(t *testing.T) {
+	s := testStructure()
+	require.Empty(t, arch.NoCycles().Check(s))
+
+	s.AddRelation(model.Relation{SourceID: "R", TargetID: "S", Path: "Repository.service"})
+	s.AddComponent(model.Component{ID: "X", Name: "Self"}, "")
+	s.AddRelation(model.Relation{SourceID: "X", TargetID: "X", Path: "Self.self"})
+
+	violations := arch.NoCycles().Check(s)
+	require.Len(t, violations, 2)
+	require.Equal(t, []model.Relation{
+		{SourceID: "R", TargetID: "S", Path: "Repository.service"},
+		{SourceID: "S", TargetID: "R"},
+	}, violations[0].Relations)
+	require.Equal(t, []model.Relation{
+		{SourceID: "X", TargetID: "X", Path: "Self.self"},
+	}, violations[1].Relations)
+	require.Equal(t,
+		"components must not form cycles: `Self` (X) -> `Self` (X) via `Self.self`",
+		violations[1].Error())
+}
+
+func TestNoCycles_scraped_structure(t *testing.T) {
+	c := scraper.NewConfiguration(
+		"github.com/krzysztofreczek/go-structurizr/pkg/internal/test",
+	)
+
+	s := scraper.NewScraper(c).Scrape(test.NewRootHasInfoWithCircularDependencies())
+	require.Len(t, arch.NoCycles().Check(s), 1)
+
+	s = scraper.NewScraper(c).Scrape(test.NewRootHasInfoWithNestedComponents())
+	require.Empty(t, arch.NoCycles().Check(s))
+}
+
+func TestRequireDescription(t *testing.T) {
+	s := testStructure()
+
+	violations := arch.RequireDescription().Check(s)
+	require.Len(t, violations, 1)
+	require.Equal(t, []model.Component{s.Components["R"]}, violations[0].Components)
+	require.Equal(t, "components must have a description: `Repository` (R)", violations[0].Error())
+
+	require.Empty(t, arch.RequireDescription("HANDLER", "SERVICE").Check(s))
+}
+
+func TestMaxOutgoingRelations(t *testing.T) {
+	s := testStructure()
+
+	violations := arch.MaxOutgoingRelations(1).Check(s)
+	require.Len(t, violations, 1)
+	require.Equal(t, "H", violations[0].Relations[0].SourceID)
+	require.Len(t, violations[0].Relations, 2)
+	require.Equal(t,
+		"components must have at most 1 outgoing relations: "+
+			"`Handler` (H) -> `Repository` (R) via `Handler.repository`, `Handler` (H) -> `Service` (S)",
+		violations[0].Error())
+
+	require.Empty(t, arch.MaxOutgoingRelations(2).Check(s))
+	require.Empty(t, arch.MaxOutgoingRelations(1, "SERVICE").Check(s))
+}
+
+func TestCheck(t *testing.T) {
+	s := testStructure()
+
+	err := arch.Check(s, arch.NoCycles(), arch.MaxOutgoingRelations(2))
+	require.NoError(t, err)
+
+	err = arch.Check(s,
+		arch.NoDependency("HANDLER", "REPOSITORY"),
+		arch.RequireDescription(),
+		arch.NoCycles(),
+	)
+	require.Error(t, err)
+	require.Equal(t, "2 architecture rule violation(s):\n"+
+		"  - components tagged `HANDLER` must not depend on components tagged `REPOSITORY`: "+
+		"`Handler` (H) -> `Repository` (R) via `Handler.repository`\n"+
+		"  - components must have a description: `Repository` (R)",
+		err.Error())
+
+	var violations arch.Violations
+	require.True(t, errors.As(err, &violations))
+	require.Len(t, violations, 2)
+
+	var violation arch.Violation
+	require.True(t, errors.As(err, &violation))
+	require.Equal(t, "components tagged `HANDLER` must not depend on components tagged `REPOSITORY`", violation.Rule)
+}
@@ -0,0 +1,285 @@
+// Package graph provides queries and metrics over the graph of components
+// and relations of scraped structures.
+//
+// All the returned component IDs are ordered, so that the results are
+// deterministic regardless of the ordering of the structure maps.
+// Relations to components that are not a part of the structure are ignored.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+// Graph is a directed graph of components connected by relations.
+type Graph struct {
+	s            model.Structure
+	ids          []string
+	dependencies map[string][]string
+	dependents   map[string][]string
+}
+
+// NewGraph creates and returns a Graph of the Structure.
+func NewGraph(s model.Structure) Graph {
+	g := Graph{
+		s:            s,
+		ids:          make([]string, 0, len(s.Components)),
+		dependencies: make(map[string][]string),
+		dependents:   make(map[string][]string),
+	}
+
+	for id := range s.Components {
+		g.ids = append(g.ids, id)
+	}
+	sort.Strings(g.ids)
+
+	for _, srcID := range g.ids {
+		for trgID := range s.Relations[srcID] {
+			if _, ok := s.Components[trgID]; !ok {
+				continue
+			}
+			g.dependencies[srcID] = append(g.dependencies[srcID], trgID)
+			g.dependents[trgID] = append(g.dependents[trgID], srcID)
+		}
+	}
+
+	for _, ids := range g.dependencies {
+		sort.Strings(ids)
+	}
+	for _, ids := range g.dependents {
+		sort.Strings(ids)
+	}
+
+	return g
+}
+
+// Components returns the IDs of all the components.
+func (g Graph) Components() []string {
+	return copyIDs(g.ids)
+}
+
+// Dependencies returns the IDs of the components the component
+// directly depends on.
+func (g Graph) Dependencies(id string) []string {
+	return copyIDs(g.dependencies[id])
+}
+
+// Dependents returns the IDs of the components directly depending
+// on the component.
+func (g Graph) Dependents(id string) []string {
+	return copyIDs(g.dependents[id])
+}
+
+// Relations returns the outgoing relations of the component,
+// ordered by the IDs of their targets.
+func (g Graph) Relations(id string) []model.Relation {
+	relations := make([]model.Relation, 0, len(g.dependencies[id]))
+	for _, trgID := range g.dependencies[id] {
+		relations = append(relations, g.relation(id, trgID))
+	}
+	return relations
+}
+
+// Reachable returns the IDs of the components reachable from the component,
+// i.e. its direct and transitive dependencies. The component itself is
+// included only if it is a part of a cycle.
+func (g Graph) Reachable(id string) []string {
+	return g.reachable(id, g.dependencies)
+}
+
+// ReverseReachable returns the IDs of the components the component is
+// reachable from, i.e. its direct and transitive dependents. The component
+// itself is included only if it is a part of a cycle.
+func (g Graph) ReverseReachable(id string) []string {
+	return g.reachable(id, g.dependents)
+}
+
+func (g Graph) reachable(id string, edges map[string][]string) []string {
+	visited := make(map[string]struct{})
+	queue := append(make([]string, 0), edges[id]...)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		if _, ok := visited[next]; ok {
+			continue
+		}
+		visited[next] = struct{}{}
+		queue = append(queue, edges[next]...)
+	}
+
+	ids := make([]string, 0, len(visited))
+	for visitedID := range visited {
+		ids = append(ids, visitedID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ShortestPath returns the relations forming the shortest path leading from
+// the source to the target component, and whether such a path exists.
+//
+// If there are multiple shortest paths, the one through the components
+// of the lowest IDs is returned.
+func (g Graph) ShortestPath(srcID string, trgID string) ([]model.Relation, bool) {
+	if _, ok := g.s.Components[srcID]; !ok {
+		return nil, false
+	}
+
+	previous := map[string]string{srcID: ""}
+	queue := []string{srcID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.dependencies[id] {
+			if next == trgID {
+				return g.path(previous, id, trgID), true
+			}
+			if _, ok := previous[next]; ok {
+				continue
+			}
+			previous[next] = id
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, false
+}
+
+func (g Graph) path(previous map[string]string, lastID string, trgID string) []model.Relation {
+	path := []model.Relation{g.relation(lastID, trgID)}
+	for id := lastID; previous[id] != ""; id = previous[id] {
+		path = append(path, g.relation(previous[id], id))
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func (g Graph) relation(srcID string, trgID string) model.Relation {
+	r := g.s.Relations[srcID][trgID]
+	r.SourceID, r.TargetID = srcID, trgID
+	return r
+}
+
+// CycleError is returned when the components cannot be ordered
+// because they form cycles.
+//
+// Cycles contains the IDs of the components of each of the cycles.
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e CycleError) Error() string {
+	cycles := make([]string, len(e.Cycles))
+	for i, ids := range e.Cycles {
+		cycles[i] = "[" + strings.Join(ids, ", ") + "]"
+	}
+	return fmt.Sprintf("components form cycles: %s", strings.Join(cycles, ", "))
+}
+
+// TopologicalOrder returns the IDs of all the components ordered so that
+// each component precedes the components it depends on.
+//
+// It returns CycleError if the components form cycles.
+func (g Graph) TopologicalOrder() ([]string, error) {
+	inDegree := make(map[string]int, len(g.ids))
+	for _, id := range g.ids {
+		inDegree[id] = len(g.dependents[id])
+	}
+
+	ready := make([]string, 0)
+	for _, id := range g.ids {
+		if inDegree[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]string, 0, len(g.ids))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		for _, next := range g.dependencies[id] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(order) < len(g.ids) {
+		return nil, CycleError{Cycles: g.Cycles()}
+	}
+	return order, nil
+}
+
+// Metrics describes the position of a component in the graph.
+//
+// FanIn is the number of components directly depending on the component.
+// FanOut is the number of components the component directly depends on.
+// Depth is the length of the shortest path leading to the component from
+// any component without dependents, or -1 if there is no such path,
+// e.g. for components forming cycles only.
+type Metrics struct {
+	FanIn  int
+	FanOut int
+	Depth  int
+}
+
+// Metrics returns the metrics of all the components, indexed by their IDs.
+func (g Graph) Metrics() map[string]Metrics {
+	depths := g.depths()
+
+	metrics := make(map[string]Metrics, len(g.ids))
+	for _, id := range g.ids {
+		depth, ok := depths[id]
+		if !ok {
+			depth = -1
+		}
+
+		metrics[id] = Metrics{
+			FanIn:  len(g.dependents[id]),
+			FanOut: len(g.dependencies[id]),
+			Depth:  depth,
+		}
+	}
+	return metrics
+}
+
+func (g Graph) depths() map[string]int {
+	depths := make(map[string]int)
+	queue := make([]string, 0)
+	for _, id := range g.ids {
+		if len(g.dependents[id]) == 0 {
+			depths[id] = 0
+			queue = append(queue, id)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.dependencies[id] {
+			if _, ok := depths[next]; ok {
+				continue
+			}
+			depths[next] = depths[id] + 1
+			queue = append(queue, next)
+		}
+	}
+	return depths
+}
+
+func copyIDs(ids []string) []string {
+	return append(make([]string, 0, len(ids)), ids...)
+}
@@ -0,0 +1,129 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/graph"
+	"github.com/krzysztofreczek/go-structurizr/pkg/internal/test"
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/scraper"
+	"github.com/stretchr/testify/require"
+)
+
+// testStructure returns the following structure:
+//
+//	A -> B -> C -> D
+//	A -> C
+//	E -> F -> E
+//	G -> G
+func testStructure() model.Structure {
+	s := model.NewStructure()
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		s.AddComponent(model.Component{ID: id}, "")
+	}
+	s.AddRelation(model.Relation{SourceID: "A", TargetID: "B", Path: "A.b"})
+	s.AddRelation(model.Relation{SourceID: "B", TargetID: "C", Path: "B.c"})
+	s.AddRelation(model.Relation{SourceID: "C", TargetID: "D", Path: "C.d"})
+	s.AddRelation(model.Relation{SourceID: "A", TargetID: "C", Path: "A.c"})
+	s.AddRelation(model.Relation{SourceID: "E", TargetID: "F"})
+	s.AddRelation(model.Relation{SourceID: "F", TargetID: "E"})
+	s.AddRelation(model.Relation{SourceID: "G", TargetID: "G"})
+	s.AddRelation(model.Relation{SourceID: "D", TargetID: "UNKNOWN"})
+	return s
+}
+
+func TestGraph_Dependencies(t *testing.T) {
+	g := graph.NewGraph(testStructure())
+
+	require.Equal(t, []string{"A", "B", "C", "D", "E", "F", "G"}, g.Components())
+	require.Equal(t, []string{"B", "C"}, g.Dependencies("A"))
+	require.Equal(t, []string{}, g.Dependencies("D"))
+	require.Equal(t, []string{"A", "B"}, g.Dependents("C"))
+	require.Equal(t, []string{}, g.Dependents("A"))
+	require.Equal(t, []model.Relation{
+		{SourceID: "A", TargetID: "B", Path: "A.b"},
+		{SourceID: "A", TargetID: "C", Path: "A.c"},
+	}, g.Relations("A"))
+}
+
+func TestGraph_Reachable(t *testing.T) {
+	g := graph.NewGraph(testStructure())
+
+	require.Equal(t, []string{"B", "C", "D"}, g.Reachable("A"))
+	require.Equal(t, []string{}, g.Reachable("D"))
+	require.Equal(t, []string{"E", "F"}, g.Reachable("E"))
+	require.Equal(t, []string{"A", "B", "C"}, g.ReverseReachable("D"))
+	require.Equal(t, []string{"G"}, g.ReverseReachable("G"))
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g := graph.NewGraph(testStructure())
+
+	path, ok := g.ShortestPath("A", "D")
+	require.True(t, ok)
+	require.Equal(t, []model.Relation{
+		{SourceID: "A", TargetID: "C", Path: "A.c"},
+		{SourceID: "C", TargetID: "D", Path: "C.d"},
+	}, path)
+
+	path, ok = g.ShortestPath("E", "E")
+	require.True(t, ok)
+	require.Len(t, path, 2)
+
+	_, ok = g.ShortestPath("D", "A")
+	require.False(t, ok)
+
+	_, ok = g.ShortestPath("UNKNOWN", "A")
+	require.False(t, ok)
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	g := graph.NewGraph(testStructure())
+
+	require.Equal(t, [][]string{{"A"}, {"B"}, {"C"}, {"D"}, {"E", "F"}, {"G"}}, g.StronglyConnectedComponents())
+	require.Equal(t, [][]string{{"E", "F"}, {"G"}}, g.Cycles())
+}
+
+func TestGraph_TopologicalOrder(t *testing.T) {
+	g := graph.NewGraph(testStructure())
+
+	_, err := g.TopologicalOrder()
+	require.Equal(t, graph.CycleError{Cycles: [][]string{{"E", "F"}, {"G"}}}, err)
+	require.Equal(t, "components form cycles: [E, F], [G]", err.Error())
+
+	s := testStructure()
+	delete(s.Components, "E")
+	delete(s.Components, "F")
+	delete(s.Components, "G")
+
+	order, err := graph.NewGraph(s).TopologicalOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B", "C", "D"}, order)
+}
+
+func TestGraph_Metrics(t *testing.T) {
+	g := graph.NewGraph(testStructure())
+
+	require.Equal(t, map[string]graph.Metrics{
+		"A": {FanIn: 0, FanOut: 2, Depth: 0},
+		"B": {FanIn: 1, FanOut: 1, Depth: 1},
+		"C": {FanIn: 2, FanOut: 1, Depth: 1},
+		"D": {FanIn: 1, FanOut: 0, Depth: 2},
+		"E": {FanIn: 1, FanOut: 1, Depth: -1},
+		"F": {FanIn: 1, FanOut: 1, Depth: -1},
+		"G": {FanIn: 1, FanOut: 1, Depth: -1},
+	}, g.Metrics())
+}
+
+func TestGraph_scraped_structure(t *testing.T) {
+	c := scraper.NewConfiguration(
+		"github.com/krzysztofreczek/go-structurizr/pkg/internal/test",
+	)
+	s := scraper.NewScraper(c).Scrape(test.NewRootHasInfoWithCircularDependencies())
+
+	g := graph.NewGraph(s)
+	require.Len(t, g.Cycles(), 1)
+
+	_, err := g.TopologicalOrder()
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"sort"
+)
+
+// StronglyConnectedComponents returns the groups of component IDs in which
+// every component is reachable from every other one, computed with Tarjan's
+// algorithm. Each component belongs to exactly one group.
+func (g Graph) StronglyConnectedComponents() [][]string {
+	t := tarjan{
+		g:       g,
+		index:   make(map[string]int),
+		lowLink: make(map[string]int),
+		onStack: make(map[string]bool),
+	}
+
+	for _, id := range g.ids {
+		if _, visited := t.index[id]; !visited {
+			t.visit(id)
+		}
+	}
+
+	sort.Slice(t.groups, func(i, j int) bool {
+		return t.groups[i][0] < t.groups[j][0]
+	})
+	return t.groups
+}
+
+// Cycles returns the groups of component IDs forming cycles, i.e. the
+// strongly connected components of more than one component and the
+// components depending on themselves.
+func (g Graph) Cycles() [][]string {
+	cycles := make([][]string, 0)
+	for _, ids := range g.StronglyConnectedComponents() {
+		if len(ids) > 1 || g.dependsOn(ids[0], ids[0]) {
+			cycles = append(cycles, ids)
+		}
+	}
+	return cycles
+}
+
+func (g Graph) dependsOn(srcID string, trgID string) bool {
+	for _, id := range g.dependencies[srcID] {
+		if id == trgID {
+			return true
+		}
+	}
+	return false
+}
+
+type tarjan struct {
+	g       Graph
+	next    int
+	index   map[string]int
+	lowLink map[string]int
+	onStack map[string]bool
+	stack   []string
+	groups  [][]string
+}
+
+func (t *tarjan) visit(id string) {
+	t.index[id] = t.next
+	t.lowLink[id] = t.next
+	t.next++
+	t.stack = append(t.stack, id)
+	t.onStack[id] = true
+
+	for _, next := range t.g.dependencies[id] {
+		if _, visited := t.index[next]; !visited {
+			t.visit(next)
+			t.lowLink[id] = min(t.lowLink[id], t.lowLink[next])
+		} else if t.onStack[next] {
+			t.lowLink[id] = min(t.lowLink[id], t.index[next])
+		}
+	}
+
+	if t.lowLink[id] != t.index[id] {
+		return
+	}
+
+	group := make([]string, 0)
+	for {
+		last := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[last] = false
+		group = append(group, last)
+		if last == id {
+			break
+		}
+	}
+
+	sort.Strings(group)
+	t.groups = append(t.groups, group)
+}
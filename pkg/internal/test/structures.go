@@ -1,6 +1,10 @@
 package test
 
-import "github.com/krzysztofreczek/go-structurizr/pkg/model"
+import (
+	"time"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
 
 type PublicInterface interface {
 	DoSomethingPublic()
@@ -990,3 +994,279 @@ func (r RootWithInterfaceWithPublicMethodWithHasInfoReturnTypeProperty) Info() m
 		"public",
 	)
 }
+
+type RootHasInfoWithRelations struct {
+}
+
+func NewRootHasInfoWithRelations() RootHasInfoWithRelations {
+	return RootHasInfoWithRelations{}
+}
+
+func (r RootHasInfoWithRelations) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithRelations",
+		"public",
+	)
+}
+
+func (r RootHasInfoWithRelations) Relations() []model.RelationInfo {
+	return []model.RelationInfo{
+		model.RelationTo(
+			model.ComponentInfo("test.Database", "database", "PostgreSQL", "DB"),
+			"reads and writes",
+			"SQL",
+		),
+		{
+			Target:      model.ComponentInfo("test.Queue", "queue", "Kafka"),
+			Description: "publishes events",
+			Tags:        []string{"ASYNC"},
+		},
+	}
+}
+
+type GenericRepository[T any] struct {
+	item T
+}
+
+type RootWithGenericRepositories struct {
+	Components GenericRepository[PublicComponent]
+	Infos      GenericRepository[PublicComponentHasInfo]
+}
+
+func NewRootWithGenericRepositories() RootWithGenericRepositories {
+	return RootWithGenericRepositories{}
+}
+
+func (r RootWithGenericRepositories) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootWithGenericRepositories",
+		"public",
+	)
+}
+
+type RootHasInfoWithAnonymousStructs struct {
+	config struct {
+		Component PublicComponentHasInfo
+	}
+	nested struct {
+		inner struct {
+			Component PublicComponent
+		}
+	}
+}
+
+func NewRootHasInfoWithAnonymousStructs() RootHasInfoWithAnonymousStructs {
+	return RootHasInfoWithAnonymousStructs{}
+}
+
+func (r RootHasInfoWithAnonymousStructs) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithAnonymousStructs",
+		"public",
+	)
+}
+
+type PublicCloser interface {
+	Close() error
+}
+
+type PublicHandlerFunc func(c PublicComponentHasInfo) error
+
+type PublicClosingComponent struct{}
+
+func (c *PublicClosingComponent) Close() error {
+	return nil
+}
+
+type RootHasInfoWithMatchedFields struct {
+	Handler PublicHandlerFunc
+	Closer  PublicClosingComponent
+	Store   PublicComponent `role:"database"`
+	Cache   privateComponent
+}
+
+func NewRootHasInfoWithMatchedFields() RootHasInfoWithMatchedFields {
+	return RootHasInfoWithMatchedFields{}
+}
+
+func (r RootHasInfoWithMatchedFields) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithMatchedFields",
+		"public",
+	)
+}
+
+type RootHasInfoWithAnnotatedFields struct {
+	Orders    PublicComponent         `c4:"name=Orders DB,tech=PostgreSQL,tags=DB|SQL"`
+	Payments  *PublicComponent        `c4:"name=Payments DB,tech=MySQL"`
+	Component PublicComponentHasInfo  `c4:"desc=annotated"`
+	Location  *time.Location          `c4:"name=Location"`
+	Skipped   privateComponentHasInfo `c4:"-"`
+	Invalid   privateComponent        `c4:"name"`
+}
+
+func NewRootHasInfoWithAnnotatedFields() RootHasInfoWithAnnotatedFields {
+	return RootHasInfoWithAnnotatedFields{}
+}
+
+func (r RootHasInfoWithAnnotatedFields) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithAnnotatedFields",
+		"public",
+	)
+}
+
+type PublicNamedOptions map[string]string
+
+type RootHasInfoWithAnnotatedNonStructFields struct {
+	Store   PublicInterface    `c4:"name=Orders DB,tech=PostgreSQL"`
+	Options PublicNamedOptions `c4:"name=Options"`
+}
+
+func NewRootHasInfoWithAnnotatedNonStructFields() RootHasInfoWithAnnotatedNonStructFields {
+	return RootHasInfoWithAnnotatedNonStructFields{}
+}
+
+func (r RootHasInfoWithAnnotatedNonStructFields) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithAnnotatedNonStructFields",
+		"public",
+	)
+}
+
+type PublicRepository struct {
+	Client PublicComponentHasInfo
+}
+
+func (r PublicRepository) Info() model.Info {
+	return model.ComponentInfo(
+		"test.PublicRepository",
+		"public",
+	)
+}
+
+type PublicNamedRepository struct {
+	Client PublicComponentHasInfo
+}
+
+func (r PublicNamedRepository) Info() model.Info {
+	return model.ComponentInfo(
+		"test.PublicNamedRepository",
+		"public",
+	)
+}
+
+func (r PublicNamedRepository) InstanceID() string {
+	return "users"
+}
+
+type RootHasInfoWithInstances struct {
+	Orders   *PublicRepository
+	Payments *PublicRepository
+	Users    PublicNamedRepository
+	Accounts PublicNamedRepository
+}
+
+func NewRootHasInfoWithInstances() RootHasInfoWithInstances {
+	return RootHasInfoWithInstances{}
+}
+
+func (r RootHasInfoWithInstances) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithInstances",
+		"public",
+	)
+}
+
+type PublicRecursiveComponent struct {
+	Next *PublicRecursiveComponent
+}
+
+type RootHasInfoWithRecursiveComponent struct {
+	Chain PublicRecursiveComponent
+}
+
+func NewRootHasInfoWithRecursiveComponent() RootHasInfoWithRecursiveComponent {
+	return RootHasInfoWithRecursiveComponent{}
+}
+
+func (r RootHasInfoWithRecursiveComponent) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithRecursiveComponent",
+		"public",
+	)
+}
+
+type PublicRecursiveComponentHasInfo struct {
+	Next *PublicRecursiveComponentHasInfo
+}
+
+func (r PublicRecursiveComponentHasInfo) Info() model.Info {
+	return model.ComponentInfo(
+		"test.PublicRecursiveComponentHasInfo",
+		"public",
+	)
+}
+
+type RootHasInfoWithRecursiveComponentHasInfo struct {
+	Head PublicRecursiveComponentHasInfo
+	Tail PublicRecursiveComponentHasInfo
+}
+
+func NewRootHasInfoWithRecursiveComponentHasInfo() RootHasInfoWithRecursiveComponentHasInfo {
+	return RootHasInfoWithRecursiveComponentHasInfo{}
+}
+
+func (r RootHasInfoWithRecursiveComponentHasInfo) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithRecursiveComponentHasInfo",
+		"public",
+	)
+}
+
+type RootHasInfoWithManyElements struct {
+	Values   []PublicComponentHasInfo
+	Pointers map[string]*PublicComponentHasInfo
+}
+
+func NewRootHasInfoWithManyElements() RootHasInfoWithManyElements {
+	return RootHasInfoWithManyElements{
+		Values: make([]PublicComponentHasInfo, 5),
+		Pointers: map[string]*PublicComponentHasInfo{
+			"1": {}, "2": {}, "3": {}, "4": {}, "5": {},
+		},
+	}
+}
+
+func (r RootHasInfoWithManyElements) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithManyElements",
+		"public",
+	)
+}
+
+type PublicPanickingComponent struct {
+	config *struct {
+		name string
+	}
+}
+
+func (c PublicPanickingComponent) Info() model.Info {
+	return model.ComponentInfo(c.config.name)
+}
+
+type RootHasInfoWithPanickingComponent struct {
+	Panicking PublicPanickingComponent
+	Value     PublicComponentHasInfo
+}
+
+func NewRootHasInfoWithPanickingComponent() RootHasInfoWithPanickingComponent {
+	return RootHasInfoWithPanickingComponent{}
+}
+
+func (r RootHasInfoWithPanickingComponent) Info() model.Info {
+	return model.ComponentInfo(
+		"test.RootHasInfoWithPanickingComponent",
+		"public",
+	)
+}
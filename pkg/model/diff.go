@@ -0,0 +1,257 @@
+package model
+
+import (
+	"sort"
+)
+
+// Tags marking components and relations of the union of two structures,
+// see StructureDiff.Union.
+const (
+	DiffTagAdded   = "ADDED"
+	DiffTagRemoved = "REMOVED"
+	DiffTagChanged = "CHANGED"
+)
+
+// Fields of a component that are compared by Diff.
+const (
+	ComponentFieldKind        = "Kind"
+	ComponentFieldName        = "Name"
+	ComponentFieldDescription = "Description"
+	ComponentFieldTechnology  = "Technology"
+	ComponentFieldTags        = "Tags"
+)
+
+// ComponentChange describes a component present in both compared structures
+// whose details have changed.
+//
+// Old and New are the component in the old and the new structure respectively.
+// Fields lists the names of the fields that differ, e.g. `Description`.
+type ComponentChange struct {
+	ID     string
+	Old    Component
+	New    Component
+	Fields []string
+}
+
+// StructureDiff describes the differences between two structures.
+//
+// Old and New are the compared structures.
+// AddedComponents and RemovedComponents contain the components present
+// only in the new or only in the old structure, ordered by their IDs.
+// ChangedComponents contains the components present in both structures
+// with different details, ordered by their IDs.
+// AddedRelations and RemovedRelations contain the relations present only
+// in the new or only in the old structure, ordered by the IDs of their
+// source and target components.
+type StructureDiff struct {
+	Old               Structure
+	New               Structure
+	AddedComponents   []Component
+	RemovedComponents []Component
+	ChangedComponents []ComponentChange
+	AddedRelations    []Relation
+	RemovedRelations  []Relation
+}
+
+// Diff compares the old and the new structure.
+//
+// Components are matched by their IDs and relations by the IDs of the
+// components they connect. Tags are compared regardless of their order.
+func Diff(oldStructure Structure, newStructure Structure) StructureDiff {
+	d := StructureDiff{
+		Old:               oldStructure,
+		New:               newStructure,
+		AddedComponents:   make([]Component, 0),
+		RemovedComponents: make([]Component, 0),
+		ChangedComponents: make([]ComponentChange, 0),
+		AddedRelations:    make([]Relation, 0),
+		RemovedRelations:  make([]Relation, 0),
+	}
+
+	for _, id := range sortedComponentIDs(newStructure) {
+		c := newStructure.Components[id]
+		oc, exists := oldStructure.Components[id]
+		if !exists {
+			d.AddedComponents = append(d.AddedComponents, c)
+			continue
+		}
+
+		fields := changedComponentFields(oc, c)
+		if len(fields) > 0 {
+			d.ChangedComponents = append(d.ChangedComponents, ComponentChange{
+				ID:     id,
+				Old:    oc,
+				New:    c,
+				Fields: fields,
+			})
+		}
+	}
+
+	for _, id := range sortedComponentIDs(oldStructure) {
+		if _, exists := newStructure.Components[id]; !exists {
+			d.RemovedComponents = append(d.RemovedComponents, oldStructure.Components[id])
+		}
+	}
+
+	d.AddedRelations = missingRelations(newStructure, oldStructure)
+	d.RemovedRelations = missingRelations(oldStructure, newStructure)
+
+	return d
+}
+
+// IsEmpty checks whether the compared structures have no differences.
+func (d StructureDiff) IsEmpty() bool {
+	return len(d.AddedComponents) == 0 &&
+		len(d.RemovedComponents) == 0 &&
+		len(d.ChangedComponents) == 0 &&
+		len(d.AddedRelations) == 0 &&
+		len(d.RemovedRelations) == 0
+}
+
+// Union returns a Structure containing the components and relations of both
+// compared structures.
+//
+// Added, removed and changed components, as well as added and removed
+// relations, are marked with DiffTagAdded, DiffTagRemoved and DiffTagChanged
+// tags respectively. The tag is prepended to the existing tags, so that it
+// selects the style the element is rendered with.
+func (d StructureDiff) Union() Structure {
+	s := NewStructure()
+
+	for id, c := range d.New.Components {
+		s.Components[id] = withComponentTag(c, "")
+	}
+	for _, c := range d.AddedComponents {
+		s.Components[c.ID] = withComponentTag(c, DiffTagAdded)
+	}
+	for _, c := range d.RemovedComponents {
+		s.Components[c.ID] = withComponentTag(c, DiffTagRemoved)
+	}
+	for _, c := range d.ChangedComponents {
+		s.Components[c.ID] = withComponentTag(c.New, DiffTagChanged)
+	}
+
+	for srcID, relations := range d.New.Relations {
+		for trgID, r := range relations {
+			r.SourceID, r.TargetID = srcID, trgID
+			setRelation(s, withRelationTag(r, ""))
+		}
+	}
+	for _, r := range d.AddedRelations {
+		setRelation(s, withRelationTag(r, DiffTagAdded))
+	}
+	for _, r := range d.RemovedRelations {
+		setRelation(s, withRelationTag(r, DiffTagRemoved))
+	}
+
+	return s
+}
+
+func sortedComponentIDs(s Structure) []string {
+	ids := make([]string, 0, len(s.Components))
+	for id := range s.Components {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func changedComponentFields(oldComponent Component, newComponent Component) []string {
+	fields := make([]string, 0)
+	if oldComponent.Kind != newComponent.Kind {
+		fields = append(fields, ComponentFieldKind)
+	}
+	if oldComponent.Name != newComponent.Name {
+		fields = append(fields, ComponentFieldName)
+	}
+	if oldComponent.Description != newComponent.Description {
+		fields = append(fields, ComponentFieldDescription)
+	}
+	if oldComponent.Technology != newComponent.Technology {
+		fields = append(fields, ComponentFieldTechnology)
+	}
+	if !equalTags(oldComponent.Tags, newComponent.Tags) {
+		fields = append(fields, ComponentFieldTags)
+	}
+	return fields
+}
+
+func equalTags(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// missingRelations returns the relations of the structure s that
+// are not present in the other structure.
+func missingRelations(s Structure, other Structure) []Relation {
+	relations := make([]Relation, 0)
+	for _, srcID := range sortedComponentIDsOfRelations(s) {
+		trgIDs := make([]string, 0, len(s.Relations[srcID]))
+		for trgID := range s.Relations[srcID] {
+			trgIDs = append(trgIDs, trgID)
+		}
+		sort.Strings(trgIDs)
+
+		for _, trgID := range trgIDs {
+			if _, exists := other.Relations[srcID][trgID]; exists {
+				continue
+			}
+
+			r := s.Relations[srcID][trgID]
+			r.SourceID, r.TargetID = srcID, trgID
+			relations = append(relations, r)
+		}
+	}
+	return relations
+}
+
+func sortedComponentIDsOfRelations(s Structure) []string {
+	ids := make([]string, 0, len(s.Relations))
+	for id := range s.Relations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func withComponentTag(c Component, tag string) Component {
+	c.Tags = withTag(c.Tags, tag)
+	return c
+}
+
+func withRelationTag(r Relation, tag string) Relation {
+	r.Tags = withTag(r.Tags, tag)
+	return r
+}
+
+// withTag returns a copy of the tags with the tag prepended,
+// so that the union does not share tags with the compared structures.
+func withTag(tags []string, tag string) []string {
+	if tag == "" {
+		if tags == nil {
+			return nil
+		}
+		return append(make([]string, 0, len(tags)), tags...)
+	}
+	return append([]string{tag}, tags...)
+}
+
+func setRelation(s Structure, r Relation) {
+	if _, ok := s.Relations[r.SourceID]; !ok {
+		s.Relations[r.SourceID] = make(map[string]Relation)
+	}
+	s.Relations[r.SourceID][r.TargetID] = r
+}
@@ -0,0 +1,75 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	old := model.NewStructure()
+	old.AddComponent(model.Component{ID: "ROOT", Name: "root", Tags: []string{"A", "B"}}, "")
+	old.AddComponent(model.Component{ID: "KEPT", Name: "kept", Description: "old"}, "ROOT")
+	old.AddComponent(model.Component{ID: "REMOVED", Name: "removed"}, "ROOT")
+
+	new := model.NewStructure()
+	new.AddComponent(model.Component{ID: "ROOT", Name: "root", Tags: []string{"B", "A"}}, "")
+	new.AddComponent(model.Component{ID: "KEPT", Name: "kept", Description: "new", Tags: []string{"C"}}, "ROOT")
+	new.AddComponent(model.Component{ID: "ADDED", Name: "added"}, "KEPT")
+
+	d := model.Diff(old, new)
+	require.False(t, d.IsEmpty())
+
+	require.Equal(t, []model.Component{new.Components["ADDED"]}, d.AddedComponents)
+	require.Equal(t, []model.Component{old.Components["REMOVED"]}, d.RemovedComponents)
+	require.Equal(t, []model.ComponentChange{
+		{
+			ID:  "KEPT",
+			Old: old.Components["KEPT"],
+			New: new.Components["KEPT"],
+			Fields: []string{
+				model.ComponentFieldDescription,
+				model.ComponentFieldTags,
+			},
+		},
+	}, d.ChangedComponents)
+	require.Equal(t, []model.Relation{{SourceID: "KEPT", TargetID: "ADDED"}}, d.AddedRelations)
+	require.Equal(t, []model.Relation{{SourceID: "ROOT", TargetID: "REMOVED"}}, d.RemovedRelations)
+}
+
+func TestDiff_same_structures(t *testing.T) {
+	s := model.NewStructure()
+	s.AddComponent(model.Component{ID: "ROOT", Name: "root"}, "")
+	s.AddComponent(model.Component{ID: "CHILD", Name: "child"}, "ROOT")
+
+	require.True(t, model.Diff(s, s).IsEmpty())
+	require.True(t, model.Diff(model.Structure{}, model.NewStructure()).IsEmpty())
+}
+
+func TestStructureDiff_Union(t *testing.T) {
+	old := model.NewStructure()
+	old.AddComponent(model.Component{ID: "ROOT", Tags: []string{"ROOT"}}, "")
+	old.AddComponent(model.Component{ID: "KEPT", Description: "old", Tags: []string{"DB"}}, "ROOT")
+	old.AddComponent(model.Component{ID: "REMOVED"}, "ROOT")
+
+	new := model.NewStructure()
+	new.AddComponent(model.Component{ID: "ROOT", Tags: []string{"ROOT"}}, "")
+	new.AddComponent(model.Component{ID: "KEPT", Description: "new", Tags: []string{"DB"}}, "ROOT")
+	new.AddComponent(model.Component{ID: "ADDED"}, "KEPT")
+
+	u := model.Diff(old, new).Union()
+
+	require.Len(t, u.Components, 4)
+	require.Equal(t, []string{"ROOT"}, u.Components["ROOT"].Tags)
+	require.Equal(t, []string{model.DiffTagChanged, "DB"}, u.Components["KEPT"].Tags)
+	require.Equal(t, "new", u.Components["KEPT"].Description)
+	require.Equal(t, []string{model.DiffTagAdded}, u.Components["ADDED"].Tags)
+	require.Equal(t, []string{model.DiffTagRemoved}, u.Components["REMOVED"].Tags)
+
+	require.Empty(t, u.Relations["ROOT"]["KEPT"].Tags)
+	require.Equal(t, []string{model.DiffTagAdded}, u.Relations["KEPT"]["ADDED"].Tags)
+	require.Equal(t, []string{model.DiffTagRemoved}, u.Relations["ROOT"]["REMOVED"].Tags)
+
+	require.Equal(t, []string{"DB"}, new.Components["KEPT"].Tags)
+}
@@ -9,6 +9,28 @@ type HasInfo interface {
 	Info() Info
 }
 
+// HasInstanceID represents a simple getter method that returns the key
+// identifying the instance of a component.
+//
+// The key is used by the scraper configured to scrape each instance
+// as a separate component, e.g. repositories of different tables.
+// Instances of the same type returning the same key are merged.
+type HasInstanceID interface {
+	InstanceID() string
+}
+
+// HasRelations represents a simple getter method that returns outgoing
+// relations of a component.
+//
+// The HasRelations interface allows a component to declare relations to
+// components that are not a part of the Go object graph, e.g. databases,
+// message brokers or third-party APIs. All components that implement
+// this interface are automatically detected by the default implementation
+// of the scraper.
+type HasRelations interface {
+	Relations() []RelationInfo
+}
+
 const (
 	infoKindComponent = "component"
 )
@@ -66,3 +88,41 @@ func info(kind string, s ...string) Info {
 func (i Info) IsZero() bool {
 	return i.Kind == ""
 }
+
+// RelationInfo struct contains details about an outgoing relation of a component.
+//
+// Target is the information about the component the relation points to.
+// Description provides an explanation of the relation.
+// Technology describes the technology the relation is based on.
+// Tags is a set of generic strings used to group and reference relations.
+type RelationInfo struct {
+	Target      Info
+	Description string
+	Technology  string
+	Tags        []string
+}
+
+// RelationTo creates a new relation pointing to the target component.
+// Variadic arguments are assigned sequentially to the remaining RelationInfo properties.
+func RelationTo(target Info, s ...string) RelationInfo {
+	info := RelationInfo{
+		Target: target,
+		Tags:   make([]string, 0),
+	}
+
+	if len(s) > 0 {
+		info.Description = s[0]
+	}
+
+	if len(s) > 1 {
+		info.Technology = s[1]
+	}
+
+	for i, tag := range s {
+		if i > 1 {
+			info.Tags = append(info.Tags, tag)
+		}
+	}
+
+	return info
+}
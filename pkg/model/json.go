@@ -0,0 +1,130 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// structureJSON is the versioned JSON representation of the Structure.
+//
+// Components are ordered by their IDs and relations by the IDs of their
+// source and target components, so that the same structure is always
+// encoded the same way.
+type structureJSON struct {
+	Version    int             `json:"version"`
+	Components []componentJSON `json:"components"`
+	Relations  []relationJSON  `json:"relations"`
+}
+
+type componentJSON struct {
+	ID          string   `json:"id"`
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Technology  string   `json:"technology"`
+	Tags        []string `json:"tags"`
+}
+
+type relationJSON struct {
+	SourceID   string   `json:"source_id"`
+	TargetID   string   `json:"target_id"`
+	Label      string   `json:"label"`
+	Path       string   `json:"path"`
+	Kind       string   `json:"kind"`
+	Technology string   `json:"technology"`
+	Tags       []string `json:"tags"`
+}
+
+// MarshalJSON encodes the Structure as versioned JSON with deterministic
+// ordering of components and relations.
+func (s Structure) MarshalJSON() ([]byte, error) {
+	out := structureJSON{
+		Version:    version,
+		Components: make([]componentJSON, 0, len(s.Components)),
+		Relations:  make([]relationJSON, 0),
+	}
+
+	for _, c := range s.Components {
+		out.Components = append(out.Components, componentJSON(c))
+	}
+	sort.Slice(out.Components, func(i, j int) bool {
+		return out.Components[i].ID < out.Components[j].ID
+	})
+
+	for srcID, relations := range s.Relations {
+		for trgID, r := range relations {
+			r.SourceID, r.TargetID = srcID, trgID
+			out.Relations = append(out.Relations, relationJSON(r))
+		}
+	}
+	sort.Slice(out.Relations, func(i, j int) bool {
+		ri, rj := out.Relations[i], out.Relations[j]
+		if ri.SourceID != rj.SourceID {
+			return ri.SourceID < rj.SourceID
+		}
+		return ri.TargetID < rj.TargetID
+	})
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the Structure from JSON produced by MarshalJSON.
+//
+// It returns an error if the JSON has been produced by an incompatible
+// version of the library.
+func (s *Structure) UnmarshalJSON(b []byte) error {
+	var in structureJSON
+	err := json.Unmarshal(b, &in)
+	if err != nil {
+		return err
+	}
+
+	if in.Version != version {
+		return errors.Errorf(
+			"unsupported structure version %d, expected %d", in.Version, version)
+	}
+
+	*s = NewStructure()
+
+	for _, c := range in.Components {
+		s.Components[c.ID] = Component(c)
+	}
+
+	for _, r := range in.Relations {
+		if _, ok := s.Relations[r.SourceID]; !ok {
+			s.Relations[r.SourceID] = make(map[string]Relation)
+		}
+		s.Relations[r.SourceID][r.TargetID] = Relation(r)
+	}
+
+	return nil
+}
+
+// WriteJSON writes the Structure encoded as indented JSON to any `io.Writer`.
+//
+// It returns an error if the writer cannot be used.
+func WriteJSON(w io.Writer, s Structure) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode structure")
+	}
+
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// ReadJSON reads the Structure encoded as JSON from any `io.Reader`.
+//
+// It returns an error if the content cannot be decoded or has been produced
+// by an incompatible version of the library.
+func ReadJSON(r io.Reader) (Structure, error) {
+	var s Structure
+	err := json.NewDecoder(r).Decode(&s)
+	if err != nil {
+		return Structure{}, errors.Wrap(err, "could not decode structure")
+	}
+	return s, nil
+}
@@ -0,0 +1,117 @@
+package model_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/internal/test"
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/scraper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructure_JSON_round_trip(t *testing.T) {
+	tests := []struct {
+		name      string
+		structure interface{}
+	}{
+		{
+			name:      "empty",
+			structure: test.NewRootEmpty(),
+		},
+		{
+			name:      "nested components",
+			structure: test.NewRootHasInfoWithNestedComponents(),
+		},
+		{
+			name:      "circular dependencies",
+			structure: test.NewRootHasInfoWithCircularDependencies(),
+		},
+		{
+			name:      "explicit relations",
+			structure: test.NewRootHasInfoWithRelations(),
+		},
+		{
+			name:      "generic repositories",
+			structure: test.NewRootWithGenericRepositories(),
+		},
+		{
+			name:      "anonymous structs",
+			structure: test.NewRootHasInfoWithAnonymousStructs(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				"github.com/krzysztofreczek/go-structurizr/pkg/internal/test",
+			)
+			expected := scraper.NewScraper(c).Scrape(tt.structure)
+
+			buf := bytes.Buffer{}
+			err := model.WriteJSON(&buf, expected)
+			require.NoError(t, err)
+
+			actual, err := model.ReadJSON(&buf)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+
+			expectedChecksum, err := expected.Checksum()
+			require.NoError(t, err)
+			actualChecksum, err := actual.Checksum()
+			require.NoError(t, err)
+			require.Equal(t, expectedChecksum, actualChecksum)
+		})
+	}
+}
+
+func TestStructure_MarshalJSON_is_deterministic(t *testing.T) {
+	s := model.NewStructure()
+	for _, id := range []string{"C", "A", "B"} {
+		s.AddComponent(model.Component{ID: id, Tags: []string{"TAG"}}, "")
+	}
+	s.AddRelation(model.Relation{SourceID: "B", TargetID: "C"})
+	s.AddRelation(model.Relation{SourceID: "A", TargetID: "C", Label: "uses"})
+	s.AddRelation(model.Relation{SourceID: "A", TargetID: "B"})
+
+	expected, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		actual, err := json.Marshal(s)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(actual))
+	}
+
+	var decoded struct {
+		Version    int
+		Components []struct{ ID string }
+		Relations  []struct {
+			SourceID string `json:"source_id"`
+			TargetID string `json:"target_id"`
+		}
+	}
+	err = json.Unmarshal(expected, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, 1, decoded.Version)
+	require.Len(t, decoded.Components, 3)
+	require.Equal(t, "A", decoded.Components[0].ID)
+	require.Equal(t, "B", decoded.Components[1].ID)
+	require.Equal(t, "C", decoded.Components[2].ID)
+	require.Len(t, decoded.Relations, 3)
+	require.Equal(t, "A", decoded.Relations[0].SourceID)
+	require.Equal(t, "B", decoded.Relations[0].TargetID)
+	require.Equal(t, "A", decoded.Relations[1].SourceID)
+	require.Equal(t, "C", decoded.Relations[1].TargetID)
+	require.Equal(t, "B", decoded.Relations[2].SourceID)
+}
+
+func TestReadJSON_unsupported_version(t *testing.T) {
+	_, err := model.ReadJSON(strings.NewReader(
+		`{"version": 2, "components": [], "relations": []}`,
+	))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported structure version 2")
+}
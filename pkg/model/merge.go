@@ -0,0 +1,361 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	kindContainer     = "container"
+	containerIDPrefix = "container_"
+)
+
+var nonAlphanumericRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ConflictPolicy defines how components with the same ID but different
+// details are merged.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyError fails the merge. It is the default policy.
+	ConflictPolicyError ConflictPolicy = "error"
+	// ConflictPolicyKeepFirst keeps the component of the first structure.
+	ConflictPolicyKeepFirst ConflictPolicy = "keep-first"
+	// ConflictPolicyKeepLast keeps the component of the last structure.
+	ConflictPolicyKeepLast ConflictPolicy = "keep-last"
+)
+
+// ComponentConflictError is reported when merged structures contain
+// components with the same ID but different details.
+type ComponentConflictError struct {
+	ID                string
+	Source            string
+	ConflictingSource string
+	Fields            []string
+}
+
+func (e ComponentConflictError) Error() string {
+	return fmt.Sprintf("component `%s` of `%s` conflicts with the one of `%s` on fields %s",
+		e.ID, e.ConflictingSource, e.Source, strings.Join(e.Fields, ", "))
+}
+
+// MergeSource is a structure to be merged, e.g. a structure scraped
+// from a single service.
+//
+// Name identifies the source. It is used as the namespace of the IDs
+// and the name of the container of the source components.
+type MergeSource struct {
+	Name      string
+	Structure Structure
+}
+
+// Source returns a MergeSource of the given name.
+func Source(name string, s Structure) MergeSource {
+	return MergeSource{
+		Name:      name,
+		Structure: s,
+	}
+}
+
+// Merge merges the structures into a single one.
+//
+// Components with the same ID are merged into a single component.
+// It returns an error if they have different details.
+func Merge(structures ...Structure) (Structure, error) {
+	sources := make([]MergeSource, len(structures))
+	for i, s := range structures {
+		sources[i] = Source(fmt.Sprintf("#%d", i), s)
+	}
+	return NewMerger().Build().Merge(sources...)
+}
+
+// Merger merges structures from multiple sources, e.g. multiple services,
+// into a single structure, e.g. a system landscape.
+//
+// Merge returns the merged structure.
+//
+// It returns an error if the sources have conflicting components
+// and the conflict policy is ConflictPolicyError, if the sources
+// are not named while namespaces or containers are enabled, or if
+// the ID of a container collides with the ID of another component.
+type Merger interface {
+	Merge(sources ...MergeSource) (Structure, error)
+}
+
+type merger struct {
+	namespaces     bool
+	containers     bool
+	containerTags  []string
+	conflictPolicy ConflictPolicy
+}
+
+func newMerger(
+	namespaces bool,
+	containers bool,
+	containerTags []string,
+	conflictPolicy ConflictPolicy,
+) Merger {
+	return merger{
+		namespaces:     namespaces,
+		containers:     containers,
+		containerTags:  containerTags,
+		conflictPolicy: conflictPolicy,
+	}
+}
+
+// NewMerger returns a new, empty Merger builder.
+func NewMerger() MergerBuilder {
+	return &mergerBuilder{
+		merger: merger{
+			containerTags:  make([]string, 0),
+			conflictPolicy: ConflictPolicyError,
+		},
+	}
+}
+
+// MergerBuilder simplifies the creation of a default Merger implementation.
+//
+// WithNamespaces prefixes the IDs of components with the names of their sources,
+// so that components of different sources are never merged.
+// WithContainers adds a component of the `container` kind for each of the sources,
+// being the parent of the source components. The containers are tagged with
+// the given tags.
+// WithConflictPolicy sets how components with the same ID but different details
+// are merged. It defaults to ConflictPolicyError.
+//
+// Build returns a default Merger implementation based on the provided configuration.
+type MergerBuilder interface {
+	WithNamespaces() MergerBuilder
+	WithContainers(tags ...string) MergerBuilder
+	WithConflictPolicy(p ConflictPolicy) MergerBuilder
+
+	Build() Merger
+}
+
+type mergerBuilder struct {
+	merger
+}
+
+// WithNamespaces prefixes the IDs of components with the names of their
+// sources, e.g. `orders_123` for the component `123` of the `orders` source.
+func (b *mergerBuilder) WithNamespaces() MergerBuilder {
+	b.namespaces = true
+	return b
+}
+
+// WithContainers adds a component of the `container` kind, named after
+// the source, for each of the sources.
+//
+// The container becomes the parent of the source components that are not
+// reachable from any other component of the source.
+func (b *mergerBuilder) WithContainers(tags ...string) MergerBuilder {
+	b.containers = true
+	b.containerTags = append(b.containerTags, tags...)
+	return b
+}
+
+// WithConflictPolicy sets how components with the same ID but different
+// details are merged.
+func (b *mergerBuilder) WithConflictPolicy(p ConflictPolicy) MergerBuilder {
+	if p != "" {
+		b.conflictPolicy = p
+	}
+	return b
+}
+
+// Build returns a default Merger implementation based on the provided configuration.
+func (b mergerBuilder) Build() Merger {
+	return newMerger(
+		b.namespaces,
+		b.containers,
+		b.containerTags,
+		b.conflictPolicy,
+	)
+}
+
+// Merge merges the structures of the sources in the given order.
+func (m merger) Merge(sources ...MergeSource) (Structure, error) {
+	merged := NewStructure()
+	componentSources := make(map[string]string)
+	containerIDs := make(map[string]struct{})
+
+	for _, src := range sources {
+		s, err := m.prepare(src)
+		if err != nil {
+			return Structure{}, err
+		}
+		if m.containers {
+			containerIDs[containerID(src.Name)] = struct{}{}
+		}
+
+		for _, id := range sortedComponentIDs(s) {
+			c := s.Components[id]
+
+			existing, exists := merged.Components[id]
+			if _, ok := containerIDs[id]; ok && exists {
+				return Structure{}, errors.Errorf(
+					"container ID `%s` of sources `%s` and `%s` collides with another component",
+					id, componentSources[id], src.Name)
+			}
+			if exists {
+				fields := changedComponentFields(existing, c)
+				if len(fields) == 0 {
+					continue
+				}
+
+				switch m.conflictPolicy {
+				case ConflictPolicyKeepFirst:
+					continue
+				case ConflictPolicyKeepLast:
+				case ConflictPolicyError:
+					return Structure{}, ComponentConflictError{
+						ID:                id,
+						Source:            componentSources[id],
+						ConflictingSource: src.Name,
+						Fields:            fields,
+					}
+				default:
+					return Structure{}, errors.Errorf("unknown conflict policy `%s`", m.conflictPolicy)
+				}
+			}
+
+			merged.Components[id] = c
+			componentSources[id] = src.Name
+		}
+
+		for srcID, relations := range s.Relations {
+			for trgID, r := range relations {
+				r.SourceID, r.TargetID = srcID, trgID
+				merged.AddRelation(r)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// prepare returns the structure of the source with namespaced IDs
+// and the container component, if enabled.
+func (m merger) prepare(src MergeSource) (Structure, error) {
+	if (m.namespaces || m.containers) && namespace(src.Name) == "" {
+		return Structure{}, errors.Errorf(
+			"source `%s` must be named with at least one alphanumeric character", src.Name)
+	}
+
+	s := NewStructure()
+
+	for id, c := range src.Structure.Components {
+		c.ID = m.namespacedID(src.Name, id)
+		s.Components[c.ID] = c
+	}
+
+	for srcID, relations := range src.Structure.Relations {
+		for trgID, r := range relations {
+			r.SourceID = m.namespacedID(src.Name, srcID)
+			r.TargetID = m.namespacedID(src.Name, trgID)
+			setRelation(s, r)
+		}
+	}
+
+	if m.containers {
+		if _, ok := s.Components[containerID(src.Name)]; ok {
+			return Structure{}, errors.Errorf(
+				"container ID `%s` of source `%s` collides with one of its components",
+				containerID(src.Name), src.Name)
+		}
+		m.addContainer(s, src.Name)
+	}
+
+	return s, nil
+}
+
+func (m merger) namespacedID(name string, id string) string {
+	if !m.namespaces {
+		return id
+	}
+	return namespace(name) + "_" + id
+}
+
+// addContainer adds the container of the source components and relations
+// from the container to the components that are not reachable from any
+// other component.
+func (m merger) addContainer(s Structure, name string) {
+	ids := sortedComponentIDs(s)
+
+	container := Component{
+		ID:   containerID(name),
+		Kind: kindContainer,
+		Name: name,
+		Tags: append(make([]string, 0, len(m.containerTags)), m.containerTags...),
+	}
+	s.Components[container.ID] = container
+
+	reached := make(map[string]struct{})
+	for _, id := range rootComponentIDs(s, ids) {
+		s.AddRelation(Relation{SourceID: container.ID, TargetID: id})
+		reach(s, id, reached)
+	}
+
+	// components forming cycles are not reachable from any root component
+	for _, id := range ids {
+		if _, ok := reached[id]; ok {
+			continue
+		}
+		s.AddRelation(Relation{SourceID: container.ID, TargetID: id})
+		reach(s, id, reached)
+	}
+}
+
+// rootComponentIDs returns the IDs of the components
+// without incoming relations, in the given order.
+func rootComponentIDs(s Structure, ids []string) []string {
+	targets := make(map[string]struct{})
+	for srcID, relations := range s.Relations {
+		for trgID := range relations {
+			if srcID != trgID {
+				targets[trgID] = struct{}{}
+			}
+		}
+	}
+
+	roots := make([]string, 0)
+	for _, id := range ids {
+		if _, ok := targets[id]; !ok {
+			roots = append(roots, id)
+		}
+	}
+	return roots
+}
+
+func reach(s Structure, id string, reached map[string]struct{}) {
+	if _, ok := reached[id]; ok {
+		return
+	}
+	reached[id] = struct{}{}
+
+	trgIDs := make([]string, 0, len(s.Relations[id]))
+	for trgID := range s.Relations[id] {
+		trgIDs = append(trgIDs, trgID)
+	}
+	sort.Strings(trgIDs)
+
+	for _, trgID := range trgIDs {
+		reach(s, trgID, reached)
+	}
+}
+
+// containerID returns the ID of the container of the source of the given
+// name, prefixed to avoid collisions with the namespaced component IDs.
+func containerID(name string) string {
+	return containerIDPrefix + namespace(name)
+}
+
+// namespace returns the name with all non-alphanumeric characters replaced
+// with underscores, so that it can be used as a part of component IDs.
+func namespace(name string) string {
+	return strings.Trim(nonAlphanumericRegexp.ReplaceAllString(name, "_"), "_")
+}
@@ -0,0 +1,195 @@
+package model_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func serviceStructure(description string) model.Structure {
+	s := model.NewStructure()
+	s.AddComponent(model.Component{ID: "HANDLER", Name: "Handler"}, "")
+	s.AddComponent(model.Component{ID: "DB", Name: "Database", Description: description}, "HANDLER")
+	return s
+}
+
+func TestMerge(t *testing.T) {
+	s1 := serviceStructure("db")
+	s2 := serviceStructure("db")
+	s2.AddComponent(model.Component{ID: "QUEUE", Name: "Queue"}, "HANDLER")
+
+	merged, err := model.Merge(s1, s2)
+	require.NoError(t, err)
+	require.Len(t, merged.Components, 3)
+	require.Contains(t, merged.Relations["HANDLER"], "DB")
+	require.Contains(t, merged.Relations["HANDLER"], "QUEUE")
+
+	merged, err = model.Merge()
+	require.NoError(t, err)
+	require.Equal(t, model.NewStructure(), merged)
+}
+
+func TestMerge_conflict(t *testing.T) {
+	_, err := model.Merge(serviceStructure("first"), serviceStructure("last"))
+	require.Error(t, err)
+	require.Equal(t, model.ComponentConflictError{
+		ID:                "DB",
+		Source:            "#0",
+		ConflictingSource: "#1",
+		Fields:            []string{model.ComponentFieldDescription},
+	}, err)
+}
+
+func TestMerger_Merge_conflict_policy(t *testing.T) {
+	tests := []struct {
+		name                string
+		policy              model.ConflictPolicy
+		expectedDescription string
+	}{
+		{
+			name:                "keep first",
+			policy:              model.ConflictPolicyKeepFirst,
+			expectedDescription: "first",
+		},
+		{
+			name:                "keep last",
+			policy:              model.ConflictPolicyKeepLast,
+			expectedDescription: "last",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := model.NewMerger().
+				WithConflictPolicy(tt.policy).
+				Build().
+				Merge(
+					model.Source("orders", serviceStructure("first")),
+					model.Source("payments", serviceStructure("last")),
+				)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedDescription, merged.Components["DB"].Description)
+		})
+	}
+
+	_, err := model.NewMerger().
+		WithConflictPolicy("unknown").
+		Build().
+		Merge(
+			model.Source("orders", serviceStructure("first")),
+			model.Source("payments", serviceStructure("last")),
+		)
+	require.Error(t, err)
+}
+
+func TestMerger_Merge_namespaces_and_containers(t *testing.T) {
+	payments := serviceStructure("payments db")
+	payments.AddComponent(model.Component{ID: "A", Name: "A"}, "")
+	payments.AddComponent(model.Component{ID: "B", Name: "B"}, "A")
+	payments.AddRelation(model.Relation{SourceID: "B", TargetID: "A"})
+
+	merged, err := model.NewMerger().
+		WithNamespaces().
+		WithContainers("SERVICE").
+		Build().
+		Merge(
+			model.Source("orders-api", serviceStructure("orders db")),
+			model.Source("payments", payments),
+		)
+	require.NoError(t, err)
+
+	require.Equal(t, model.Component{
+		ID:   "container_orders_api",
+		Kind: "container",
+		Name: "orders-api",
+		Tags: []string{"SERVICE"},
+	}, merged.Components["container_orders_api"])
+	require.Equal(t, "orders db", merged.Components["orders_api_DB"].Description)
+	require.Equal(t, "orders_api_DB", merged.Components["orders_api_DB"].ID)
+	require.Equal(t, "payments db", merged.Components["payments_DB"].Description)
+	require.Len(t, merged.Components, 8)
+
+	require.Equal(t, []string{"orders_api_HANDLER"}, relationTargets(merged, "container_orders_api"))
+	require.Equal(t, []string{"orders_api_DB"}, relationTargets(merged, "orders_api_HANDLER"))
+	require.Equal(t, []string{"payments_A", "payments_HANDLER"}, relationTargets(merged, "container_payments"))
+	require.Equal(t, "payments_B", merged.Relations["payments_A"]["payments_B"].TargetID)
+	require.Equal(t, "payments_B", merged.Relations["payments_B"]["payments_A"].SourceID)
+}
+
+func TestMerger_Merge_containers_with_cycles(t *testing.T) {
+	s := model.NewStructure()
+	s.AddComponent(model.Component{ID: "A"}, "")
+	s.AddComponent(model.Component{ID: "B"}, "A")
+	s.AddRelation(model.Relation{SourceID: "B", TargetID: "A"})
+
+	merged, err := model.NewMerger().
+		WithContainers().
+		Build().
+		Merge(model.Source("service", s))
+	require.NoError(t, err)
+	require.Equal(t, []string{"A"}, relationTargets(merged, "container_service"))
+}
+
+func TestMerger_Merge_container_id_collisions(t *testing.T) {
+	var tests = []struct {
+		name    string
+		sources []model.MergeSource
+	}{
+		{
+			name: "component of the same source",
+			sources: []model.MergeSource{
+				model.Source("orders", structureWithComponent("container_orders")),
+			},
+		},
+		{
+			name: "component of another source",
+			sources: []model.MergeSource{
+				model.Source("orders", model.NewStructure()),
+				model.Source("payments", structureWithComponent("container_orders")),
+			},
+		},
+		{
+			name: "container of another source",
+			sources: []model.MergeSource{
+				model.Source("orders-api", model.NewStructure()),
+				model.Source("orders api", model.NewStructure()),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := model.NewMerger().
+				WithContainers().
+				Build().
+				Merge(tt.sources...)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "container ID `container_orders")
+		})
+	}
+}
+
+func structureWithComponent(id string) model.Structure {
+	s := model.NewStructure()
+	s.AddComponent(model.Component{ID: id}, "")
+	return s
+}
+
+func TestMerger_Merge_unnamed_source(t *testing.T) {
+	_, err := model.NewMerger().
+		WithNamespaces().
+		Build().
+		Merge(model.Source("", model.NewStructure()))
+	require.Error(t, err)
+}
+
+func relationTargets(s model.Structure, srcID string) []string {
+	targets := make([]string, 0)
+	for trgID := range s.Relations[srcID] {
+		targets = append(targets, trgID)
+	}
+	sort.Strings(targets)
+	return targets
+}
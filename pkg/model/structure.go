@@ -26,20 +26,63 @@ type Component struct {
 	Tags        []string
 }
 
+// Kinds of relations between components, describing how the target
+// component has been reached from the source one.
+const (
+	RelationKindField        = "field"
+	RelationKindMethodArg    = "method-arg"
+	RelationKindMethodReturn = "method-return"
+	RelationKindSliceElement = "slice-element"
+	RelationKindMapValue     = "map-value"
+	RelationKindExplicit     = "explicit"
+)
+
+// Relation is an open structure representing the details of a connection
+// between two components.
+//
+// SourceID is the ID of the component the relation starts from.
+// TargetID is the ID of the component the relation points to.
+// Label is a short description of the relation.
+// Path is the path of fields and methods leading from the source to the target,
+// e.g. `Collector.spanHandler.processor`.
+// Kind describes how the target has been reached from the source, e.g. `field`.
+// Technology describes the technology the relation is based on.
+// Tags is a set of generic strings used to group and reference relations.
+type Relation struct {
+	SourceID   string
+	TargetID   string
+	Label      string
+	Path       string
+	Kind       string
+	Technology string
+	Tags       []string
+}
+
+// IsZero checks whether the relation carries no details
+// apart from the IDs of the components it connects.
+func (r Relation) IsZero() bool {
+	return r.Label == "" &&
+		r.Path == "" &&
+		r.Kind == "" &&
+		r.Technology == "" &&
+		len(r.Tags) == 0
+}
+
 // Structure is an open structure representing the entire scraped system.
 //
 // Components contains all the scraped components, indexed by their IDs.
-// Relations contains all the connections between components, indexed by their IDs.
+// Relations contains all the connections between components, indexed by
+// the IDs of source and target components respectively.
 type Structure struct {
 	Components map[string]Component
-	Relations  map[string]map[string]struct{}
+	Relations  map[string]map[string]Relation
 }
 
 // NewStructure creates and returns an empty Structure.
 func NewStructure() Structure {
 	return Structure{
 		Components: make(map[string]Component),
-		Relations:  make(map[string]map[string]struct{}),
+		Relations:  make(map[string]map[string]Relation),
 	}
 }
 
@@ -49,12 +92,29 @@ func NewStructure() Structure {
 func (s Structure) AddComponent(c Component, parentID string) {
 	s.Components[c.ID] = c
 	if parentID != "" {
-		_, ok := s.Relations[parentID]
-		if !ok {
-			s.Relations[parentID] = make(map[string]struct{})
-		}
-		s.Relations[parentID][c.ID] = struct{}{}
+		s.AddRelation(Relation{
+			SourceID: parentID,
+			TargetID: c.ID,
+		})
+	}
+}
+
+// AddRelation adds a relation between the source and the target components.
+//
+// Only the first relation between the same pair of components is recorded,
+// unless it carries no details, in which case it is replaced.
+func (s Structure) AddRelation(r Relation) {
+	_, ok := s.Relations[r.SourceID]
+	if !ok {
+		s.Relations[r.SourceID] = make(map[string]Relation)
+	}
+
+	existing, exists := s.Relations[r.SourceID][r.TargetID]
+	if exists && !existing.IsZero() {
+		return
 	}
+
+	s.Relations[r.SourceID][r.TargetID] = r
 }
 
 // Checksum returns a hash of the Structure.
@@ -81,9 +141,22 @@ func (s Structure) Checksum() (string, error) {
 
 		r := s.Relations[cID]
 		for _, rID := range cIDs {
-			if _, exists := r[rID]; exists {
+			if relation, exists := r[rID]; exists {
 				rel := fmt.Sprintf("%s-%s", cID, rID)
 				accu = append(accu, rel)
+
+				if relation.IsZero() {
+					continue
+				}
+
+				relation.SourceID, relation.TargetID = cID, rID
+				sort.Strings(relation.Tags)
+
+				rHash, err := structhash.Hash(relation, version)
+				if err != nil {
+					return "", err
+				}
+				accu = append(accu, rHash)
 			}
 		}
 	}
@@ -8,8 +8,9 @@ import (
 )
 
 const (
-	emptyStructChecksum  = "v1_d751713988987e9331980363e24189ce"
-	simpleStructChecksum = "v1_4cd1ab42ba6c8a15bc80d7918b7f3ec7"
+	emptyStructChecksum             = "v1_d751713988987e9331980363e24189ce"
+	simpleStructChecksum            = "v1_4cd1ab42ba6c8a15bc80d7918b7f3ec7"
+	simpleStructWithRelationDetails = "v1_1796fe828c0ee4e917909bda66cff12a"
 )
 
 func TestStructure_Checksum(t *testing.T) {
@@ -38,6 +39,11 @@ func TestStructure_Checksum(t *testing.T) {
 			structure: simpleStructureWithDifferentOrders(),
 			expected:  simpleStructChecksum,
 		},
+		{
+			name:      "simple structure with relation details",
+			structure: simpleStructureWithRelationDetails(),
+			expected:  simpleStructWithRelationDetails,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -48,6 +54,33 @@ func TestStructure_Checksum(t *testing.T) {
 	}
 }
 
+func TestStructure_AddRelation(t *testing.T) {
+	s := model.NewStructure()
+
+	s.AddComponent(model.Component{ID: "ID_2"}, "ID_1")
+	require.Equal(t, model.Relation{SourceID: "ID_1", TargetID: "ID_2"}, s.Relations["ID_1"]["ID_2"])
+
+	first := model.Relation{
+		SourceID: "ID_1",
+		TargetID: "ID_2",
+		Path:     "Component.first",
+		Kind:     model.RelationKindField,
+	}
+	s.AddRelation(first)
+	require.Equal(t, first, s.Relations["ID_1"]["ID_2"])
+
+	s.AddRelation(model.Relation{
+		SourceID: "ID_1",
+		TargetID: "ID_2",
+		Path:     "Component.second",
+		Kind:     model.RelationKindField,
+	})
+	require.Equal(t, first, s.Relations["ID_1"]["ID_2"])
+
+	s.AddComponent(model.Component{ID: "ID_2"}, "ID_1")
+	require.Equal(t, first, s.Relations["ID_1"]["ID_2"])
+}
+
 func simpleStructure() model.Structure {
 	s := model.NewStructure()
 	s.Components = map[string]model.Component{
@@ -76,7 +109,7 @@ func simpleStructure() model.Structure {
 			Tags:        []string{"TAG_1", "TAG_2"},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 			"ID_3": {},
@@ -116,7 +149,7 @@ func simpleStructureWithDifferentOrders() model.Structure {
 			Tags:        []string{"TAG_1"},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_2": {
 			"ID_3": {},
 		},
@@ -127,3 +160,15 @@ func simpleStructureWithDifferentOrders() model.Structure {
 	}
 	return s
 }
+
+func simpleStructureWithRelationDetails() model.Structure {
+	s := simpleStructure()
+	s.Relations["ID_1"]["ID_2"] = model.Relation{
+		SourceID: "ID_1",
+		TargetID: "ID_2",
+		Label:    "label",
+		Path:     "Component.field",
+		Kind:     model.RelationKindField,
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package scraper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AnnotationTagKey is the key of the struct tag annotating components
+	// of field values, e.g. `c4:"name=Orders DB,tech=PostgreSQL,tags=DB"`.
+	AnnotationTagKey = "c4"
+
+	annotationSkip         = "-"
+	annotationSeparator    = ','
+	annotationQuote        = '\''
+	annotationTagSeparator = "|"
+)
+
+// AnnotationError is reported when the struct tag annotating the field
+// cannot be parsed. The field is scraped as if it was not annotated.
+type AnnotationError struct {
+	Path string
+	Tag  string
+	Err  error
+}
+
+func (e AnnotationError) Error() string {
+	return fmt.Sprintf("invalid `%s` struct tag `%s` of field `%s`: %s",
+		AnnotationTagKey, e.Tag, e.Path, e.Err)
+}
+
+// Unwrap returns the parsing error.
+func (e AnnotationError) Unwrap() error {
+	return e.Err
+}
+
+// annotation is the component information declared with the struct tag
+// of the field. It overrides the information resolved for the value of
+// the field with the non-empty properties.
+type annotation struct {
+	info model.Info
+}
+
+// parseAnnotation parses the value of the struct tag in the format
+// `name=...,tech=...,tags=A|B,desc=...`. Values containing commas are
+// single-quoted, e.g. `desc='Reads, writes'`.
+// It returns nil if the tag is not set and whether the field is skipped.
+func parseAnnotation(tag reflect.StructTag) (*annotation, bool, error) {
+	value, ok := tag.Lookup(AnnotationTagKey)
+	if !ok {
+		return nil, false, nil
+	}
+	if value == annotationSkip {
+		return nil, true, nil
+	}
+
+	parts, err := splitAnnotation(value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	a := &annotation{}
+	for _, part := range parts {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, false, errors.Errorf(
+				"expected `key=value`, got `%s`, values containing commas must be quoted, e.g. `desc='a, b'`", part)
+		}
+
+		val = unquoteAnnotation(strings.TrimSpace(val))
+		val = strings.TrimSpace(val)
+		switch strings.TrimSpace(key) {
+		case "name":
+			a.info.Name = val
+		case "tech":
+			a.info.Technology = val
+		case "desc":
+			a.info.Description = val
+		case "tags":
+			for _, t := range strings.Split(val, annotationTagSeparator) {
+				if t = strings.TrimSpace(t); t != "" {
+					a.info.Tags = append(a.info.Tags, t)
+				}
+			}
+		default:
+			return nil, false, errors.Errorf("unknown key `%s`", key)
+		}
+	}
+
+	return a, false, nil
+}
+
+// splitAnnotation splits the value of the struct tag on the commas
+// which are not quoted.
+func splitAnnotation(value string) ([]string, error) {
+	parts := make([]string, 0)
+	quoted := false
+	start := 0
+	for i, r := range value {
+		switch {
+		case r == annotationQuote:
+			quoted = !quoted
+		case r == annotationSeparator && !quoted:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, errors.Errorf("unterminated quote in `%s`", value[start:])
+	}
+	return append(parts, value[start:]), nil
+}
+
+// unquoteAnnotation strips the quotes of the quoted value.
+func unquoteAnnotation(val string) string {
+	if len(val) >= 2 && val[0] == annotationQuote && val[len(val)-1] == annotationQuote {
+		return val[1 : len(val)-1]
+	}
+	return val
+}
+
+// apply overrides the info with the non-empty properties of the annotation.
+// The component is named after its type if no name is resolved.
+func (a *annotation) apply(info model.Info, name string) model.Info {
+	if a.info.Name != "" {
+		info.Name = a.info.Name
+	}
+	if a.info.Technology != "" {
+		info.Technology = a.info.Technology
+	}
+	if a.info.Description != "" {
+		info.Description = a.info.Description
+	}
+	if len(a.info.Tags) > 0 {
+		info.Tags = a.info.Tags
+	}
+	if info.Name == "" {
+		info.Name = name
+	}
+	return info
+}
+
+// fieldAnnotation parses the annotation of the field reached through
+// the given origin. Invalid annotations are recorded as errors and ignored.
+func (s *scraper) fieldAnnotation(o origin) (*annotation, bool) {
+	a, skip, err := parseAnnotation(o.tag)
+	if err != nil {
+		s.addError(AnnotationError{
+			Path: o.path,
+			Tag:  o.tag.Get(AnnotationTagKey),
+			Err:  err,
+		})
+		return nil, false
+	}
+	return a, skip
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseAnnotation(t *testing.T) {
+	var tests = []struct {
+		name         string
+		tag          reflect.StructTag
+		expectedInfo *model.Info
+		expectedSkip bool
+		expectedErr  string
+	}{
+		{
+			name: "not annotated",
+			tag:  `json:"orders"`,
+		},
+		{
+			name:         "skipped",
+			tag:          `c4:"-"`,
+			expectedSkip: true,
+		},
+		{
+			name: "all keys",
+			tag:  `c4:"name=Orders DB, tech=PostgreSQL,tags=DB|SQL,desc=Orders storage"`,
+			expectedInfo: &model.Info{
+				Name:        "Orders DB",
+				Technology:  "PostgreSQL",
+				Description: "Orders storage",
+				Tags:        []string{"DB", "SQL"},
+			},
+		},
+		{
+			name: "quoted values",
+			tag:  `c4:"name=Orders DB,desc='Reads, writes',tags='DB|SQL'"`,
+			expectedInfo: &model.Info{
+				Name:        "Orders DB",
+				Description: "Reads, writes",
+				Tags:        []string{"DB", "SQL"},
+			},
+		},
+		{
+			name:        "unquoted comma",
+			tag:         `c4:"name=Orders DB,desc=Reads, writes"`,
+			expectedErr: "expected `key=value`, got ` writes`, values containing commas must be quoted",
+		},
+		{
+			name:        "unterminated quote",
+			tag:         `c4:"name=Orders DB,desc='Reads, writes"`,
+			expectedErr: "unterminated quote in `desc='Reads, writes`",
+		},
+		{
+			name:        "unknown key",
+			tag:         `c4:"technology=PostgreSQL"`,
+			expectedErr: "unknown key `technology`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, skip, err := parseAnnotation(tt.tag)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.Nil(t, a)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSkip, skip)
+
+			if tt.expectedInfo == nil {
+				require.Nil(t, a)
+				return
+			}
+			require.NotNil(t, a)
+			require.Equal(t, *tt.expectedInfo, a.info)
+		})
+	}
+}
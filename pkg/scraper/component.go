@@ -0,0 +1,279 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+var qualifiedNameRegexp = regexp.MustCompile(`[\w.\-~/]*[./](\w+)`)
+
+func (s *scraper) addComponent(
+	pkg string,
+	typeName string,
+	info model.Info,
+	o origin,
+) model.Component {
+	if o.annotation != nil {
+		info = o.annotation.apply(info, componentName(pkg, typeName))
+	}
+	ctx := s.hookContext(pkg, typeName, o)
+	typeName = s.instanceTypeName(pkg, typeName, o)
+
+	id, ok := s.registerComponentID(pkg, typeName)
+	if !ok {
+		return model.Component{}
+	}
+	if _, ok := s.componentParents[id]; !ok && id != o.parentID {
+		s.componentParents[id] = o.parentID
+	}
+	if _, ok := s.structure.Components[id]; !ok && !s.hasComponentsCapacity(o) {
+		return model.Component{}
+	}
+
+	c, accepted := s.hookedComponent(id, info, ctx)
+	if !accepted {
+		s.debugType(ctx.Name, id, "component has been vetoed by one of the component hooks")
+		return model.Component{}
+	}
+
+	s.structure.AddComponent(c, "")
+	if o.parentID != "" {
+		s.addRelation(model.Relation{
+			SourceID: o.parentID,
+			TargetID: c.ID,
+			Path:     o.path,
+			Kind:     o.kind,
+		}, ctx)
+	}
+	return c
+}
+
+// hookedComponent returns the component of the given ID, and whether it has
+// not been vetoed by the component hooks. The hooks are called once per
+// component, the first time it is added. Components added again, e.g. when
+// reached from multiple parents, are returned as accepted by the hooks.
+func (s *scraper) hookedComponent(id string, info model.Info, ctx HookContext) (model.Component, bool) {
+	if accepted, ok := s.hooked[id]; ok {
+		return s.structure.Components[id], accepted
+	}
+
+	c := model.Component{
+		ID:          id,
+		Kind:        info.Kind,
+		Name:        info.Name,
+		Description: info.Description,
+		Technology:  info.Technology,
+		Tags:        info.Tags,
+	}
+	accepted := s.applyComponentHooks(&c, ctx)
+	s.hooked[id] = accepted
+	return c, accepted
+}
+
+// instanceTypeName returns the name the component of the type reached
+// from the origin is identified by.
+//
+// Values of annotated fields become components of their own for each field,
+// so that fields of the same type may be described differently. In the
+// InstancesSeparate mode, all the values do, unless they are identified by
+// the keys returned by `model.HasInstanceID`. Values of types referring
+// to themselves are identified by the closest parent component of the same
+// type, so that the recursion is cut off as in the InstancesMerge mode.
+func (s *scraper) instanceTypeName(pkg string, typeName string, o origin) string {
+	separate := s.config.Instances == InstancesSeparate
+	if separate && o.instance != "" {
+		return typeName + "@" + o.instance
+	}
+	if (separate || o.annotation != nil) && o.path != "" {
+		if ancestor, ok := s.ancestorTypeName(pkg, typeName, o); ok {
+			return ancestor
+		}
+		return typeName + "@" + s.instancePath(o)
+	}
+	return typeName
+}
+
+// ancestorTypeName returns the name the closest parent component
+// of the given type is identified by, if any.
+func (s *scraper) ancestorTypeName(pkg string, typeName string, o origin) (string, bool) {
+	key := fmt.Sprintf("%s.%s", pkg, typeName)
+	for id := o.parentID; id != ""; id = s.componentParents[id] {
+		registered := s.componentIDs[id]
+		if registered == key || strings.HasPrefix(registered, key+"@") {
+			return strings.TrimPrefix(registered, pkg+"."), true
+		}
+	}
+	return "", false
+}
+
+// instancePath returns the path of the field the value has been reached
+// through, qualified by the instance of the parent component, if any.
+func (s *scraper) instancePath(o origin) string {
+	_, parent, ok := strings.Cut(s.componentIDs[o.parentID], "@")
+	if ok {
+		return parent + "/" + o.path
+	}
+	return o.path
+}
+
+// addAnnotatedComponent adds the component of the value of the annotated
+// field, even if its type is not recognized as a component otherwise.
+// It returns an empty component if the field is not annotated.
+func (s *scraper) addAnnotatedComponent(
+	pkg string,
+	typeName string,
+	o origin,
+) model.Component {
+	if o.annotation == nil || typeName == "" {
+		return model.Component{}
+	}
+	return s.addComponent(pkg, typeName, model.ComponentInfo(), o)
+}
+
+// registerComponentID resolves the ID of the component of the given type
+// and makes sure it has not been taken by any other type yet.
+// Otherwise, the collision is recorded as an error.
+func (s *scraper) registerComponentID(pkg string, typeName string) (string, bool) {
+	id := s.componentID(pkg, typeName)
+	key := fmt.Sprintf("%s.%s", pkg, typeName)
+
+	registered, ok := s.componentIDs[id]
+	if !ok {
+		s.componentIDs[id] = key
+		return id, true
+	}
+	if registered == key {
+		return id, true
+	}
+
+	collision := registered + "|" + key
+	if _, ok := s.collisions[collision]; !ok {
+		s.collisions[collision] = struct{}{}
+		s.addError(IDCollisionError{
+			ID:            id,
+			Type:          registered,
+			CollidingType: key,
+		})
+	}
+	s.debugType(componentName(pkg, typeName), id,
+		"component ID is already taken by `%s`, skipping", registered)
+
+	return "", false
+}
+
+func (s *scraper) addExplicitRelations(
+	c model.Component,
+	relations []model.RelationInfo,
+) {
+	for _, r := range relations {
+		if r.Target.Name == "" {
+			s.debugType(c.Name, c.ID, "relation %+v has no target name, skipping", r)
+			continue
+		}
+
+		target := s.addComponent("", r.Target.Name, r.Target, origin{})
+		if target.ID == "" {
+			continue
+		}
+
+		s.addRelation(model.Relation{
+			SourceID:   c.ID,
+			TargetID:   target.ID,
+			Label:      r.Description,
+			Kind:       model.RelationKindExplicit,
+			Technology: r.Technology,
+			Tags:       r.Tags,
+		}, HookContext{
+			Name:      r.Target.Name,
+			Parent:    c,
+			Structure: s.structure,
+		})
+	}
+}
+
+func (s *scraper) isPackageScrappable(pkg string) bool {
+	if s.isPackageExcluded(pkg) {
+		return false
+	}
+	for _, prefix := range s.config.Packages {
+		if strings.HasPrefix(pkg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRules applies the first rule matching the given type.
+// Instantiations of generic types are also matched by their base name,
+// e.g. `pkg.Repository` matches `pkg.Repository[User]`.
+func (s *scraper) applyRules(ctx MatchContext) (model.Info, bool) {
+	baseCtx := ctx
+	baseCtx.Name = baseTypeName(ctx.Name)
+	for i, r := range s.rules {
+		if info, ok := s.applyRule(i, r, ctx, baseCtx); ok {
+			return info, true
+		}
+	}
+	return model.Info{}, false
+}
+
+// applyRule applies the rule if it matches either the context or the context
+// of the base type. Panics of the rule are recorded as errors, and the rule
+// is considered not matching.
+func (s *scraper) applyRule(i int, r ContextRule, ctx MatchContext, baseCtx MatchContext) (model.Info, bool) {
+	defer s.recoverPanic(ctx.Name, fmt.Sprintf("rule #%d", i), ctx.path)
+
+	if r.Matches(ctx) {
+		s.reportRuleHit(i)
+		return r.ApplyContext(ctx), true
+	}
+	if baseCtx.Name != ctx.Name && r.Matches(baseCtx) {
+		s.reportRuleHit(i)
+		return r.ApplyContext(baseCtx), true
+	}
+	return model.Info{}, false
+}
+
+// typeName returns the name of the type according to the configured
+// generics mode.
+func (s *scraper) typeName(name string) string {
+	if s.config.Generics == GenericsMerge {
+		return baseTypeName(name)
+	}
+	return name
+}
+
+func (s *scraper) componentID(pkg string, typeName string) string {
+	if s.config.IDStrategy != nil {
+		return s.config.IDStrategy(pkg, typeName)
+	}
+	return HashIDStrategy(pkg, typeName)
+}
+
+func componentName(pkg string, typeName string) string {
+	p := strings.Split(pkg, "/")
+	return fmt.Sprintf("%s.%s", p[len(p)-1], shortTypeName(typeName))
+}
+
+// baseTypeName strips type arguments from the name of a generic type,
+// e.g. `Repository[github.com/org/pkg.User]` becomes `Repository`.
+func baseTypeName(typeName string) string {
+	i := strings.Index(typeName, "[")
+	if i < 0 {
+		return typeName
+	}
+	return typeName[:i]
+}
+
+// shortTypeName strips package paths from type arguments of a generic type,
+// e.g. `Repository[github.com/org/pkg.User]` becomes `Repository[User]`.
+func shortTypeName(typeName string) string {
+	i := strings.Index(typeName, "[")
+	if i < 0 {
+		return typeName
+	}
+	return typeName[:i] + qualifiedNameRegexp.ReplaceAllString(typeName[i:], "$1")
+}
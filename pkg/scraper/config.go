@@ -1,15 +1,77 @@
 package scraper
 
+import (
+	"log/slog"
+	"regexp"
+)
+
 // Configuration is an open structure that holds the scraper configuration.
 //
 // Packages contain prefixes of packages for the scraper to process.
 // Any package object that does not match the provided prefixes will be omitted,
 // and its internal structure will not be scraped.
 // If no package prefixes are provided, the scraper will only process the root level of the structure.
+//
+// ExcludedPackages contain prefixes of packages for the scraper to omit,
+// even if they match any of the Packages, e.g. `github.com/org/app/internal/mocks`.
+// ExcludedPackageRegexps work the same way, but match packages against
+// regular expressions, e.g. `/generated$`.
+//
+// IDStrategy produces IDs of the scraped components. If not provided,
+// HashIDStrategy is used.
+//
+// Generics defines how instantiations of generic types are scraped.
+// If not provided, GenericsSeparate is used.
+//
+// Instances defines whether instances of the same type are scraped
+// as a single component. If not provided, InstancesMerge is used.
+//
+// Logger receives debug messages of the scraping process with the id,
+// name, type, strategy, depth (scraping level) and parent of the scraped
+// component attached.
+// If not provided, messages are written with the standard logger only if
+// the `LOG_LEVEL` environment variable is set to `DEBUG`.
+//
+// Limits bound the work of the scraper on huge structures. If not provided,
+// only recursive uses of structs are limited.
 type Configuration struct {
-	Packages []string
+	Packages               []string
+	ExcludedPackages       []string
+	ExcludedPackageRegexps []*regexp.Regexp
+	IDStrategy             IDStrategy
+	Generics               GenericsMode
+	Instances              InstancesMode
+	Logger                 *slog.Logger
+	Limits                 Limits
 }
 
+// GenericsMode defines how instantiations of generic types are scraped.
+type GenericsMode string
+
+const (
+	// GenericsSeparate makes each instantiation of a generic type a separate
+	// component, e.g. `Repository[User]` and `Repository[Order]`.
+	GenericsSeparate GenericsMode = "separate"
+	// GenericsMerge merges all instantiations of a generic type into
+	// a single component named after the type without type arguments,
+	// e.g. `Repository`.
+	GenericsMerge GenericsMode = "merge"
+)
+
+// InstancesMode defines whether instances of the same type are scraped
+// as a single component.
+type InstancesMode string
+
+const (
+	// InstancesMerge merges all instances of a type into a single component.
+	InstancesMerge InstancesMode = "merge"
+	// InstancesSeparate makes each instance of a type a separate component,
+	// e.g. two `*http.Client` instances pointing to different upstreams.
+	// Instances are identified by the keys returned by `model.HasInstanceID`,
+	// or by the paths of the fields they are reached through otherwise.
+	InstancesSeparate InstancesMode = "separate"
+)
+
 // NewConfiguration creates a Configuration with the specified package prefixes.
 //
 // It takes a variadic argument to accept multiple package prefixes.
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Errors aggregates errors encountered while scraping.
+// The scraped structure is complete except for the elements
+// the errors refer to.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the aggregated errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// PanicError is reported when user code called by the scraper panics,
+// e.g. the `Info()` method of a component or the apply function of a rule.
+// The type the code has been called for is scraped as if the code
+// returned no data.
+//
+// Type is the name of the type, Path is the path of the field the value
+// has been reached through, Source is the code that panicked, e.g. `Info()`
+// or `rule #2`, and Value is the recovered value.
+type PanicError struct {
+	Type   string
+	Path   string
+	Source string
+	Value  interface{}
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("recovered panic in %s of type `%s` at `%s`: %v",
+		e.Source, e.Type, e.Path, e.Value)
+}
+
+// recoverPanic records the recovered panic as a PanicError.
+// It must be deferred directly. Panics are propagated by plain Scrape,
+// which has no way to return the errors.
+func (s *scraper) recoverPanic(typeName string, source string, path string) {
+	if s.propagatePanics {
+		return
+	}
+	r := recover()
+	if r == nil {
+		return
+	}
+	s.addError(PanicError{
+		Type:   typeName,
+		Path:   path,
+		Source: source,
+		Value:  r,
+	})
+}
+
+func (s *scraper) addError(err error) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *scraper) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	errs := make(Errors, len(s.errs))
+	copy(errs, s.errs)
+	return errs
+}
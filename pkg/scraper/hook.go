@@ -0,0 +1,176 @@
+package scraper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// HookContext describes where the component, or the target component
+// of the relation, passed to the hook has been found.
+//
+// Pkg and Name are the package and the name of the type of the component,
+// the latter in the format `package.TypeName`.
+// Parent is the closest parent component, or the source component
+// of an explicit relation. It is empty for root components.
+// Path, Field and Tag describe the field the component has been reached
+// through from the parent component.
+// Structure is the structure scraped so far. It must not be modified.
+type HookContext struct {
+	Pkg       string
+	Name      string
+	Parent    model.Component
+	Path      string
+	Field     string
+	Tag       reflect.StructTag
+	Structure model.Structure
+}
+
+// ComponentHook is called once for each component before it is added to
+// the structure, e.g. to add tags or override names.
+//
+// The hook may modify any property of the component except for its ID.
+// Returning false vetoes the component, in which case it is not added,
+// and the values it has been reached through are scraped as if they were
+// not recognized as a component.
+type ComponentHook func(c *model.Component, ctx HookContext) bool
+
+// RelationHook is called for each relation before it is added to
+// the structure.
+//
+// The hook may modify any property of the relation except for the IDs
+// of the components it connects. Returning false vetoes the relation.
+type RelationHook func(r *model.Relation, ctx HookContext) bool
+
+// VisitHook is called for each value visited by the scraper,
+// before it is scraped.
+type VisitHook func(v reflect.Value)
+
+// OnComponent adds the specified ComponentHook to the scraper.
+// Hooks are called in order of registration.
+//
+// It returns an error if the provided hook is nil.
+func (s *scraper) OnComponent(h ComponentHook) error {
+	if h == nil {
+		return errors.New("component hook must not be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.componentHooks = append(s.componentHooks, h)
+	return nil
+}
+
+// OnRelation adds the specified RelationHook to the scraper.
+// Hooks are called in order of registration.
+//
+// It returns an error if the provided hook is nil.
+func (s *scraper) OnRelation(h RelationHook) error {
+	if h == nil {
+		return errors.New("relation hook must not be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.relationHooks = append(s.relationHooks, h)
+	return nil
+}
+
+// OnVisit adds the specified VisitHook to the scraper.
+// Hooks are called in order of registration.
+//
+// It returns an error if the provided hook is nil.
+func (s *scraper) OnVisit(h VisitHook) error {
+	if h == nil {
+		return errors.New("visit hook must not be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.visitHooks = append(s.visitHooks, h)
+	return nil
+}
+
+func (s *scraper) hookContext(pkg string, typeName string, o origin) HookContext {
+	return HookContext{
+		Pkg:       pkg,
+		Name:      componentName(pkg, typeName),
+		Parent:    s.structure.Components[o.parentID],
+		Path:      o.path,
+		Field:     o.field,
+		Tag:       o.tag,
+		Structure: s.structure,
+	}
+}
+
+// applyComponentHooks calls the component hooks, and returns whether
+// none of them vetoed the component.
+func (s *scraper) applyComponentHooks(c *model.Component, ctx HookContext) bool {
+	id := c.ID
+	defer func() {
+		c.ID = id
+	}()
+
+	for i, h := range s.componentHooks {
+		if !s.applyComponentHook(i, h, c, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyComponentHook calls the hook. Panics of the hook are recorded
+// as errors, and the component is considered not vetoed.
+func (s *scraper) applyComponentHook(i int, h ComponentHook, c *model.Component, ctx HookContext) (accepted bool) {
+	accepted = true
+	defer s.recoverPanic(ctx.Name, fmt.Sprintf("component hook #%d", i), ctx.Path)
+
+	return h(c, ctx)
+}
+
+// applyRelationHooks calls the relation hooks, and returns whether
+// none of them vetoed the relation.
+func (s *scraper) applyRelationHooks(r *model.Relation, ctx HookContext) bool {
+	sourceID, targetID := r.SourceID, r.TargetID
+	defer func() {
+		r.SourceID, r.TargetID = sourceID, targetID
+	}()
+
+	for i, h := range s.relationHooks {
+		if !s.applyRelationHook(i, h, r, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRelationHook calls the hook. Panics of the hook are recorded
+// as errors, and the relation is considered not vetoed.
+func (s *scraper) applyRelationHook(i int, h RelationHook, r *model.Relation, ctx HookContext) (accepted bool) {
+	accepted = true
+	defer s.recoverPanic(ctx.Name, fmt.Sprintf("relation hook #%d", i), ctx.Path)
+
+	return h(r, ctx)
+}
+
+// addRelation adds the relation to the structure, unless it is vetoed
+// by one of the relation hooks.
+func (s *scraper) addRelation(r model.Relation, ctx HookContext) {
+	if !s.applyRelationHooks(&r, ctx) {
+		s.debugType(ctx.Name, r.TargetID, "relation from `%s` has been vetoed by one of the relation hooks", r.SourceID)
+		return
+	}
+	s.structure.AddRelation(r)
+}
+
+// visit calls the visit hooks. Panics of the hooks are recorded as errors.
+func (s *scraper) visit(v reflect.Value, o origin) {
+	for i, h := range s.visitHooks {
+		s.applyVisitHook(i, h, v, o)
+	}
+}
+
+func (s *scraper) applyVisitHook(i int, h VisitHook, v reflect.Value, o origin) {
+	defer s.recoverPanic(v.Type().String(), fmt.Sprintf("visit hook #%d", i), o.path)
+
+	h(v)
+}
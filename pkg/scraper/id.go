@@ -0,0 +1,65 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/internal"
+	"github.com/pkg/errors"
+)
+
+const (
+	// IDStrategyHash is the YAML name of HashIDStrategy.
+	IDStrategyHash = "hash"
+	// IDStrategyReadable is the YAML name of ReadableIDStrategy.
+	IDStrategyReadable = "readable"
+)
+
+var nonAlphanumericRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// IDStrategy produces the ID of a component from the package path
+// and the name of its type.
+//
+// The package path is empty for components that do not correspond to
+// any Go type, e.g. the targets of relations declared with `model.HasRelations`.
+// The produced IDs are used as diagram aliases, hence they should consist of
+// alphanumeric characters and underscores only.
+type IDStrategy func(pkg string, typeName string) string
+
+// HashIDStrategy produces numeric IDs by hashing the full type name.
+// It is the default strategy.
+func HashIDStrategy(pkg string, typeName string) string {
+	return internal.Hash(fmt.Sprintf("%s.%s", pkg, typeName))
+}
+
+// ReadableIDStrategy produces IDs by replacing all non-alphanumeric
+// characters of the full type name with underscores,
+// e.g. `jaeger/app.SpanHandler` becomes `jaeger_app_SpanHandler`.
+func ReadableIDStrategy(pkg string, typeName string) string {
+	id := nonAlphanumericRegexp.ReplaceAllString(fmt.Sprintf("%s.%s", pkg, typeName), "_")
+	return strings.Trim(id, "_")
+}
+
+// IDCollisionError is reported when two different types resolve to the same
+// component ID. The component of the type that was scraped first is kept.
+type IDCollisionError struct {
+	ID            string
+	Type          string
+	CollidingType string
+}
+
+func (e IDCollisionError) Error() string {
+	return fmt.Sprintf("types `%s` and `%s` resolve to the same component ID `%s`",
+		e.Type, e.CollidingType, e.ID)
+}
+
+func idStrategyByName(name string) (IDStrategy, error) {
+	switch name {
+	case "", IDStrategyHash:
+		return HashIDStrategy, nil
+	case IDStrategyReadable:
+		return ReadableIDStrategy, nil
+	}
+	return nil, errors.Errorf("unknown ID strategy `%s`", name)
+}
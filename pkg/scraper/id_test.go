@@ -0,0 +1,45 @@
+package scraper_test
+
+import (
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/scraper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadableIDStrategy(t *testing.T) {
+	var tests = []struct {
+		name       string
+		pkg        string
+		typeName   string
+		expectedID string
+	}{
+		{
+			name:       "type",
+			pkg:        "jaeger/app",
+			typeName:   "SpanHandler",
+			expectedID: "jaeger_app_SpanHandler",
+		},
+		{
+			name:       "type of full package path",
+			pkg:        "github.com/jaegertracing/jaeger/cmd/collector/app",
+			typeName:   "SpanHandler",
+			expectedID: "github_com_jaegertracing_jaeger_cmd_collector_app_SpanHandler",
+		},
+		{
+			name:       "external component",
+			pkg:        "",
+			typeName:   "Orders DB",
+			expectedID: "Orders_DB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedID, scraper.ReadableIDStrategy(tt.pkg, tt.typeName))
+		})
+	}
+}
+
+func TestHashIDStrategy(t *testing.T) {
+	require.Equal(t, componentID("RootEmptyHasInfo"), scraper.HashIDStrategy(testPKG, "RootEmptyHasInfo"))
+}
@@ -0,0 +1,219 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IgnoreMode defines how types matching an IgnoreRule are treated.
+type IgnoreMode string
+
+const (
+	// IgnoreAll makes the scraper skip matching types entirely. They do not
+	// become components and their fields and methods are not scraped.
+	// It is the default mode.
+	IgnoreAll IgnoreMode = "all"
+	// IgnoreComponent prevents matching types from becoming components,
+	// but their fields and methods are still scraped. Components found
+	// within them are attached to the closest parent component.
+	IgnoreComponent IgnoreMode = "component"
+)
+
+// IgnoreRule defines an interface for rules excluding types from scraping
+// that can be registered with the scraper.
+//
+// Applies determines if the rule should be applied to a given type
+// based on its full package name and type name in the format `package.TypeName`.
+// Mode returns how the matching types are treated.
+type IgnoreRule interface {
+	Applies(
+		pkg string,
+		name string,
+	) bool
+	Mode() IgnoreMode
+}
+
+type ignoreRule struct {
+	pkgRegexes []*regexp.Regexp
+	nameRegex  *regexp.Regexp
+	mode       IgnoreMode
+}
+
+func newIgnoreRule(
+	pkgRegexes []*regexp.Regexp,
+	nameRegex *regexp.Regexp,
+	mode IgnoreMode,
+) (ignoreRule, error) {
+	switch mode {
+	case IgnoreAll, IgnoreComponent:
+	default:
+		return ignoreRule{}, errors.Errorf("unknown ignore mode `%s`", mode)
+	}
+
+	return ignoreRule{
+		pkgRegexes: pkgRegexes,
+		nameRegex:  nameRegex,
+		mode:       mode,
+	}, nil
+}
+
+// Applies determines if the rule should be applied to a given type
+// based on its full package name and type name in the format `package.TypeName`.
+//
+// A type is considered applicable if all of the following conditions are met:
+// - The package matches at least one of the rule's package regular expressions.
+// - The name matches the rule's name regular expression.
+func (r ignoreRule) Applies(
+	pkg string,
+	name string,
+) bool {
+	if !r.nameRegex.MatchString(name) {
+		return false
+	}
+	for _, rgx := range r.pkgRegexes {
+		if rgx.MatchString(pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mode returns how the types matching the rule are treated.
+func (r ignoreRule) Mode() IgnoreMode {
+	return r.mode
+}
+
+// IgnoreRuleBuilder simplifies the creation of a default IgnoreRule implementation.
+//
+// WithPkgRegexps sets the list of package regular expressions.
+// WithNameRegexp sets the name regular expression.
+// WithMode sets how the matching types are treated. It defaults to IgnoreAll.
+//
+// Build returns an `IgnoreRule` implementation constructed from the provided
+// regular expressions and mode. It will return an error if any of the provided
+// expressions are invalid and cannot be compiled, or if the mode is unknown.
+type IgnoreRuleBuilder interface {
+	WithPkgRegexps(rgx ...string) IgnoreRuleBuilder
+	WithNameRegexp(rgx string) IgnoreRuleBuilder
+	WithMode(m IgnoreMode) IgnoreRuleBuilder
+
+	Build() (IgnoreRule, error)
+}
+
+type ignoreRuleBuilder struct {
+	pkgRegexes []string
+	nameRegex  string
+	mode       IgnoreMode
+}
+
+// NewIgnoreRule returns a new, empty IgnoreRuleBuilder.
+func NewIgnoreRule() IgnoreRuleBuilder {
+	return &ignoreRuleBuilder{
+		mode: IgnoreAll,
+	}
+}
+
+// WithPkgRegexps sets a list of package regular expressions.
+func (b *ignoreRuleBuilder) WithPkgRegexps(rgx ...string) IgnoreRuleBuilder {
+	b.pkgRegexes = append(b.pkgRegexes, rgx...)
+	return b
+}
+
+// WithNameRegexp sets name regular expression.
+func (b *ignoreRuleBuilder) WithNameRegexp(rgx string) IgnoreRuleBuilder {
+	b.nameRegex = rgx
+	return b
+}
+
+// WithMode sets how the types matching the rule are treated.
+func (b *ignoreRuleBuilder) WithMode(m IgnoreMode) IgnoreRuleBuilder {
+	if m != "" {
+		b.mode = m
+	}
+	return b
+}
+
+// Build returns IgnoreRule implementation constructed from the provided
+// expressions and mode.
+//
+// In case no regular expression is provided either for name or package,
+// those will be filled with regular expression matching all string "^.*$".
+//
+// Build will return an error if at least one of the provided expressions
+// is invalid and cannot be compiled, or if the mode is unknown.
+func (b ignoreRuleBuilder) Build() (IgnoreRule, error) {
+	pkgRegexes := make([]*regexp.Regexp, 0)
+	for _, rgx := range b.pkgRegexes {
+		r, err := regexp.Compile(rgx)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"could not compile package expression `%s` "+
+					"as correct regular expression", rgx)
+		}
+		pkgRegexes = append(pkgRegexes, r)
+	}
+
+	if len(pkgRegexes) == 0 {
+		pkgRegexes = append(pkgRegexes, matchAllRegexp)
+	}
+
+	nameRegex := matchAllRegexp
+	if b.nameRegex != "" {
+		r, err := regexp.Compile(b.nameRegex)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"could not compile name expression `%s` "+
+					"as correct regular expression", b.nameRegex)
+		}
+		nameRegex = r
+	}
+
+	return newIgnoreRule(
+		pkgRegexes,
+		nameRegex,
+		b.mode,
+	)
+}
+
+// RegisterIgnoreRule adds the specified IgnoreRule to the scraper.
+//
+// It returns an error if the provided rule is nil.
+func (s *scraper) RegisterIgnoreRule(r IgnoreRule) error {
+	if r == nil {
+		return errors.New("ignore rule must not be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignoreRules = append(s.ignoreRules, r)
+	return nil
+}
+
+// ignoreMode returns the mode of the first ignore rule matching the given type.
+// Instantiations of generic types are also matched by their base name.
+func (s *scraper) ignoreMode(pkg string, name string) (IgnoreMode, bool) {
+	baseName := baseTypeName(name)
+	for _, r := range s.ignoreRules {
+		if r.Applies(pkg, name) || (baseName != name && r.Applies(pkg, baseName)) {
+			return r.Mode(), true
+		}
+	}
+	return "", false
+}
+
+// isPackageExcluded checks whether the package matches any of the excluded
+// package prefixes or regular expressions.
+func (s *scraper) isPackageExcluded(pkg string) bool {
+	for _, prefix := range s.config.ExcludedPackages {
+		if strings.HasPrefix(pkg, prefix) {
+			return true
+		}
+	}
+	for _, rgx := range s.config.ExcludedPackageRegexps {
+		if rgx != nil && rgx.MatchString(pkg) {
+			return true
+		}
+	}
+	return false
+}
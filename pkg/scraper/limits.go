@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+)
+
+// Limits bound the work of the scraper on huge structures.
+// Zero values mean no limit.
+//
+// MaxDepth limits the depth of fields and methods the scraper follows
+// from the scraped root value, which is at depth 0.
+// MaxComponents limits the number of components of the scraped structure.
+// Scraping stops once the limit is reached.
+// MaxElements limits the number of elements of maps, slices and arrays
+// sampled for scraping.
+// MaxRecursion limits how many times the same struct is scraped again,
+// after it has been scraped for the first time, within the same parent
+// component. If not provided, recursive uses are silently cut off
+// after 100 repeated scrapes.
+type Limits struct {
+	MaxDepth      int
+	MaxComponents int
+	MaxElements   int
+	MaxRecursion  int
+}
+
+// Limit identifies one of the Limits.
+type Limit string
+
+const (
+	LimitDepth      Limit = "max_depth"
+	LimitComponents Limit = "max_components"
+	LimitElements   Limit = "max_elements"
+	LimitRecursion  Limit = "max_recursion"
+)
+
+// LimitError is reported when scraping hits one of the configured Limits.
+// It is reported once per limit, with the path of the first value the limit
+// has been hit at. The structure is complete up to the limit.
+type LimitError struct {
+	Limit Limit
+	Max   int
+	Path  string
+}
+
+func (e LimitError) Error() string {
+	return fmt.Sprintf("scraping limit `%s` of %d hit at `%s`", e.Limit, e.Max, e.Path)
+}
+
+// limitExceeded records the LimitError of the given limit, if it is the first
+// time the limit is hit.
+func (s *scraper) limitExceeded(l Limit, max int, o origin) {
+	if _, ok := s.limitsHit[l]; ok {
+		return
+	}
+	s.limitsHit[l] = struct{}{}
+	s.addError(LimitError{
+		Limit: l,
+		Max:   max,
+		Path:  o.path,
+	})
+}
+
+// isTooDeep returns whether the value at the given level exceeds
+// the configured depth limit.
+func (s *scraper) isTooDeep(o origin, level int) bool {
+	max := s.config.Limits.MaxDepth
+	if max <= 0 || level <= max {
+		return false
+	}
+	s.limitExceeded(LimitDepth, max, o)
+	return true
+}
+
+// hasComponentsCapacity returns whether another component may be added
+// to the structure. Scraping is aborted once the limit is reached.
+func (s *scraper) hasComponentsCapacity(o origin) bool {
+	max := s.config.Limits.MaxComponents
+	if max <= 0 || len(s.structure.Components) < max {
+		return true
+	}
+	s.limitExceeded(LimitComponents, max, o)
+	s.aborted = true
+	return false
+}
+
+// elementsCount returns the number of elements of the collection
+// of the given length to be sampled for scraping.
+func (s *scraper) elementsCount(n int, o origin) int {
+	max := s.config.Limits.MaxElements
+	if max <= 0 || n <= max {
+		return n
+	}
+	s.limitExceeded(LimitElements, max, o)
+	return max
+}
+
+// isRecursionExceeded returns whether the struct has been scraped again
+// too many times with the given usage key. The default limit is applied the same way
+// as the configured one, but it is not reported as a LimitError.
+func (s *scraper) isRecursionExceeded(usageKey string, o origin) bool {
+	max := s.config.Limits.MaxRecursion
+	configured := max > 0
+	if !configured {
+		max = maxRecursiveScrapes
+	}
+	if s.typeCounters[usageKey] <= max {
+		return false
+	}
+	if configured {
+		s.limitExceeded(LimitRecursion, max, o)
+	}
+	return true
+}
+
+// isAborted returns whether scraping has been aborted, either because
+// the context is done or one of the limits stopping the scraping is hit.
+func (s *scraper) isAborted() bool {
+	if s.aborted {
+		return true
+	}
+	if err := s.ctx.Err(); err != nil {
+		s.addError(err)
+		s.aborted = true
+	}
+	return s.aborted
+}
+
+// withContext runs the scraping function with the given context.
+func (s *scraper) withContext(ctx context.Context, scrape func()) {
+	s.ctx = ctx
+	scrape()
+}
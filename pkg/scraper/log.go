@@ -1,22 +1,151 @@
 package scraper
 
 import (
+	"context"
 	"fmt"
+	"go/types"
 	"log"
+	"log/slog"
 	"os"
 	"reflect"
 )
 
-func (s *scraper) isDebugMode() bool {
+// newLogger returns the logger debug messages are written to.
+// If no logger is provided, messages are written with the standard logger
+// only if the `LOG_LEVEL` environment variable is set to `DEBUG`.
+func newLogger(l *slog.Logger) *slog.Logger {
+	if l != nil {
+		return l
+	}
+	if !isDebugEnv() {
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(log.Writer(), &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+}
+
+func isDebugEnv() bool {
 	level := os.Getenv("LOG_LEVEL")
 	return level == "DEBUG" || level == "debug"
 }
 
+// position describes the place in the scraped structure the scraper
+// is currently at, attached to each of the debug messages.
+type position struct {
+	strategy string
+	level    int
+	parentID string
+}
+
+func (s *scraper) isDebugMode() bool {
+	return s.logger != nil && s.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// enter moves the scraper to the given position and returns the function
+// restoring the previous one. The position is tracked only in debug mode.
+func (s *scraper) enter(strategy string, o origin, level int) func() {
+	if !s.isDebugMode() {
+		return func() {}
+	}
+
+	previous := s.position
+	s.position = position{
+		strategy: strategy,
+		level:    level,
+		parentID: o.parentID,
+	}
+	return func() {
+		s.position = previous
+	}
+}
+
 func (s *scraper) debug(v reflect.Value, format string, a ...interface{}) {
 	if !s.isDebugMode() {
 		return
 	}
 
-	m := fmt.Sprintf(format, a...)
-	log.Printf("[%s][id: %s] %s\n", componentName(v), componentID(v), m)
+	if !v.IsValid() {
+		s.log("", "", "", format, a...)
+		return
+	}
+
+	s.log(s.valueComponentName(v), s.valueComponentID(v), v.Type().String(), format, a...)
+}
+
+func (s *scraper) debugType(name string, id string, format string, a ...interface{}) {
+	if !s.isDebugMode() {
+		return
+	}
+
+	s.log(name, id, name, format, a...)
+}
+
+func (s *scraper) log(name string, id string, typ string, format string, a ...interface{}) {
+	s.logger.LogAttrs(context.Background(), slog.LevelDebug, fmt.Sprintf(format, a...),
+		slog.String("id", id),
+		slog.String("name", name),
+		slog.String("type", typ),
+		slog.String("strategy", s.position.strategy),
+		slog.Int("depth", s.position.level),
+		slog.String("parent", s.position.parentID),
+	)
+}
+
+// logErrors logs the errors encountered while scraping at the warning level,
+// for the entry points which do not return them.
+func (s *scraper) logErrors() {
+	if s.logger == nil {
+		return
+	}
+	for _, err := range s.errs {
+		s.logger.LogAttrs(context.Background(), slog.LevelWarn, "scraping error",
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func strategyName(k reflect.Kind) string {
+	switch k {
+	case reflect.Interface:
+		return "interface"
+	case reflect.Ptr:
+		return "pointer"
+	case reflect.Map:
+		return "map"
+	case reflect.Slice, reflect.Array:
+		return "iterable"
+	case reflect.Func:
+		return "function"
+	case reflect.Struct:
+		return "struct"
+	default:
+		return "noop"
+	}
+}
+
+func typeStrategyName(t types.Type) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		switch t.Underlying().(type) {
+		case *types.Struct:
+			return "struct"
+		case *types.Interface:
+			return "interface"
+		default:
+			return "named"
+		}
+	case *types.Pointer:
+		return "pointer"
+	case *types.Slice, *types.Array:
+		return "iterable"
+	case *types.Map:
+		return "map"
+	case *types.Signature:
+		return "function"
+	case *types.Struct:
+		return "struct"
+	default:
+		return "noop"
+	}
 }
@@ -0,0 +1,317 @@
+package scraper
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+// MatchContext describes the type being matched against the rules.
+//
+// Pkg is the full package name of the type.
+// Name is the name of the type in the format `package.TypeName`.
+// Kind is the kind of the type, e.g. `reflect.Struct` or `reflect.Func`.
+// Type is the type itself. It is nil for the static scraper, which never
+// instantiates the application.
+// Parent is the closest component the type has been reached from.
+// It is empty for the root type.
+// Field is the name of the struct field the value of the type has been
+// reached through, if any. Elements of slices and maps are reached through
+// the field of the collection.
+// Tag is the struct tag of that field.
+// Level is the nesting level of the type, starting at 0 for the root type.
+type MatchContext struct {
+	Pkg    string
+	Name   string
+	Kind   reflect.Kind
+	Type   reflect.Type
+	Parent model.Component
+	Field  string
+	Tag    reflect.StructTag
+	Level  int
+
+	path            string
+	implements      func(iface reflect.Type) bool
+	implementsNamed func(pkg string, name string) bool
+	hasMethod       func(name string) bool
+}
+
+// Implements checks whether the type, or the pointer to the type,
+// implements the given interface type.
+func (c MatchContext) Implements(iface reflect.Type) bool {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return false
+	}
+	if c.implements != nil {
+		return c.implements(iface)
+	}
+	return c.implementsNamed != nil && c.implementsNamed(iface.PkgPath(), iface.Name())
+}
+
+// implementsName checks whether the type, or the pointer to the type,
+// implements the interface of the given name in the format
+// `package.TypeName`.
+// Interfaces are resolved by name by the static scraper only.
+func (c MatchContext) implementsName(name string) bool {
+	idx := strings.LastIndex(name, ".")
+	if c.implementsNamed == nil || idx < 0 {
+		return false
+	}
+	return c.implementsNamed(name[:idx], name[idx+1:])
+}
+
+// HasMethod checks whether the type, or the pointer to the type,
+// has an exported method of the given name.
+func (c MatchContext) HasMethod(name string) bool {
+	if c.hasMethod == nil {
+		return false
+	}
+	return c.hasMethod(name)
+}
+
+// Matcher matches types the rules are applied to on more than their
+// package and name, e.g. on the interfaces they implement.
+//
+// Matchers can be combined with And, Or and Not.
+type Matcher interface {
+	Matches(ctx MatchContext) bool
+}
+
+// MatcherFunc is a function implementing the Matcher interface.
+type MatcherFunc func(ctx MatchContext) bool
+
+// Matches calls the function.
+func (f MatcherFunc) Matches(ctx MatchContext) bool {
+	return f(ctx)
+}
+
+// Implements returns a Matcher matching types that implement the given
+// interface type, e.g. `reflect.TypeOf((*io.Closer)(nil)).Elem()`.
+func Implements(iface reflect.Type) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		return ctx.Implements(iface)
+	})
+}
+
+func implementsName(name string) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		return ctx.implementsName(name)
+	})
+}
+
+// HasMethod returns a Matcher matching types that have an exported method
+// of the given name.
+func HasMethod(name string) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		return ctx.HasMethod(name)
+	})
+}
+
+// HasStructTag returns a Matcher matching values reached through struct
+// fields carrying a tag of the given key, e.g. `role` for `role:"database"`.
+func HasStructTag(key string) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		_, ok := ctx.Tag.Lookup(key)
+		return ok
+	})
+}
+
+// KindOf returns a Matcher matching types of any of the given kinds,
+// e.g. `reflect.Func`, `reflect.Chan` or `reflect.Map`.
+func KindOf(kinds ...reflect.Kind) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		for _, k := range kinds {
+			if ctx.Kind == k {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// And returns a Matcher matching types matched by all the given matchers.
+func And(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		for _, m := range matchers {
+			if !m.Matches(ctx) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Or returns a Matcher matching types matched by any of the given matchers.
+func Or(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		for _, m := range matchers {
+			if m.Matches(ctx) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not returns a Matcher matching types not matched by the given matcher.
+func Not(m Matcher) Matcher {
+	return MatcherFunc(func(ctx MatchContext) bool {
+		return !m.Matches(ctx)
+	})
+}
+
+// MatchingRule is implemented by rules matching types on more than their
+// package and name. The default Rule implementation implements it.
+//
+// Deprecated: MatchingRule is an alias of ContextRule, use ContextRule instead.
+type MatchingRule = ContextRule
+
+// isComponentKind checks whether values of the kind become components
+// when matched by rules without any matcher.
+func isComponentKind(k reflect.Kind) bool {
+	return k == reflect.Struct || k == reflect.Interface
+}
+
+// matchContext returns the context of the type of the given package,
+// name and kind reached from the origin at the given level.
+func (s *scraper) matchContext(pkg string, name string, kind reflect.Kind, o origin, level int) MatchContext {
+	return MatchContext{
+		Pkg:    pkg,
+		Name:   name,
+		Kind:   kind,
+		Parent: s.structure.Components[o.parentID],
+		Field:  o.field,
+		Tag:    o.tag,
+		Level:  level,
+		path:   o.path,
+	}
+}
+
+// runtimeMatchContext returns the context of the value type of the given
+// package and name reached from the origin at the given level.
+func (s *scraper) runtimeMatchContext(t reflect.Type, pkg string, name string, o origin, level int) MatchContext {
+	ctx := s.matchContext(pkg, name, t.Kind(), o, level)
+	ctx.Type = t
+	ctx.implements = func(iface reflect.Type) bool {
+		return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+	}
+	ctx.hasMethod = func(name string) bool {
+		if _, ok := t.MethodByName(name); ok {
+			return true
+		}
+		if t.Kind() == reflect.Interface {
+			return false
+		}
+		_, ok := reflect.PointerTo(t).MethodByName(name)
+		return ok
+	}
+	return ctx
+}
+
+// staticMatchContext returns the context of the type of the given
+// package and name reached from the origin at the given level.
+func (s *staticScraper) staticMatchContext(t types.Type, pkg string, name string, o origin, level int) MatchContext {
+	ctx := s.matchContext(pkg, name, typeKind(t), o, level)
+	ctx.implementsNamed = func(ifacePkg string, ifaceName string) bool {
+		iface, ok := s.lookupInterface(ifacePkg, ifaceName)
+		if !ok {
+			return false
+		}
+		return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+	}
+	ctx.hasMethod = func(name string) bool {
+		var obj types.Object
+		if types.IsInterface(t) {
+			obj, _, _ = types.LookupFieldOrMethod(t, true, nil, name)
+		} else {
+			obj, _, _ = types.LookupFieldOrMethod(types.NewPointer(t), true, nil, name)
+		}
+		m, ok := obj.(*types.Func)
+		return ok && m.Exported()
+	}
+	return ctx
+}
+
+// lookupInterface finds the interface of the given name among
+// the loaded packages.
+func (s *staticScraper) lookupInterface(pkg string, name string) (*types.Interface, bool) {
+	p, ok := s.packages[pkg]
+	if !ok || p.Types == nil {
+		return nil, false
+	}
+	obj, ok := p.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	return iface, ok
+}
+
+// typeKind maps the underlying type to the corresponding reflect.Kind.
+func typeKind(t types.Type) reflect.Kind {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	case *types.Basic:
+		return basicKind(u)
+	}
+	return reflect.Invalid
+}
+
+func basicKind(b *types.Basic) reflect.Kind {
+	switch b.Kind() {
+	case types.Bool:
+		return reflect.Bool
+	case types.Int:
+		return reflect.Int
+	case types.Int8:
+		return reflect.Int8
+	case types.Int16:
+		return reflect.Int16
+	case types.Int32:
+		return reflect.Int32
+	case types.Int64:
+		return reflect.Int64
+	case types.Uint:
+		return reflect.Uint
+	case types.Uint8:
+		return reflect.Uint8
+	case types.Uint16:
+		return reflect.Uint16
+	case types.Uint32:
+		return reflect.Uint32
+	case types.Uint64:
+		return reflect.Uint64
+	case types.Uintptr:
+		return reflect.Uintptr
+	case types.Float32:
+		return reflect.Float32
+	case types.Float64:
+		return reflect.Float64
+	case types.Complex64:
+		return reflect.Complex64
+	case types.Complex128:
+		return reflect.Complex128
+	case types.String:
+		return reflect.String
+	case types.UnsafePointer:
+		return reflect.UnsafePointer
+	}
+	return reflect.Invalid
+}
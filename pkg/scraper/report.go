@@ -0,0 +1,168 @@
+package scraper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report describes the scraping process, helping to find out why
+// the scraped structure lacks some of the expected components.
+//
+// Types are identified by their full names in the format
+// `github.com/org/pkg.TypeName`.
+//
+// VisitedTypes counts visits of the types in the scraped packages.
+// SkippedPackages counts types skipped as their packages do not match
+// the configured package prefixes or are excluded.
+// UnmatchedTypes counts visits of structs and interfaces in the scraped
+// packages that have not been recognized as components.
+// RecursionCutoffs counts types that have not been scraped further,
+// as they have been used recursively too many times.
+// Rules holds hit counts of the registered rules, in order of registration.
+// Elapsed is the duration of the scraping.
+type Report struct {
+	VisitedTypes     map[string]int
+	SkippedPackages  map[string]int
+	UnmatchedTypes   map[string]int
+	RecursionCutoffs map[string]int
+	Rules            []RuleReport
+	Elapsed          time.Duration
+}
+
+// RuleReport holds the hit count of the registered rule.
+//
+// Index is the index of the rule in order of registration.
+// Rule is the description of the rule, if it implements `fmt.Stringer`,
+// or its type otherwise.
+type RuleReport struct {
+	Index int
+	Rule  string
+	Hits  int
+}
+
+func newReport(rules []ContextRule) *Report {
+	r := &Report{
+		VisitedTypes:     make(map[string]int),
+		SkippedPackages:  make(map[string]int),
+		UnmatchedTypes:   make(map[string]int),
+		RecursionCutoffs: make(map[string]int),
+		Rules:            make([]RuleReport, len(rules)),
+	}
+	for i, rule := range rules {
+		r.Rules[i] = RuleReport{
+			Index: i,
+			Rule:  ruleDescription(rule),
+		}
+	}
+	return r
+}
+
+// UnmatchedRules returns the rules that have never been applied.
+func (r Report) UnmatchedRules() []RuleReport {
+	unmatched := make([]RuleReport, 0)
+	for _, rule := range r.Rules {
+		if rule.Hits == 0 {
+			unmatched = append(unmatched, rule)
+		}
+	}
+	return unmatched
+}
+
+// String returns the summary of the report, listing the counts
+// in descending order.
+func (r Report) String() string {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "scraped in %s\n", r.Elapsed)
+	writeReportCounts(&b, "visited types", r.VisitedTypes)
+	writeReportCounts(&b, "skipped packages", r.SkippedPackages)
+	writeReportCounts(&b, "unmatched types", r.UnmatchedTypes)
+	writeReportCounts(&b, "recursion cutoffs", r.RecursionCutoffs)
+
+	_, _ = fmt.Fprintf(&b, "rules (%d):\n", len(r.Rules))
+	for _, rule := range r.Rules {
+		_, _ = fmt.Fprintf(&b, "  - #%d %s: %d\n", rule.Index, rule.Rule, rule.Hits)
+	}
+
+	return b.String()
+}
+
+func writeReportCounts(b *strings.Builder, title string, counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	_, _ = fmt.Fprintf(b, "%s (%d):\n", title, len(keys))
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(b, "  - %s: %d\n", k, counts[k])
+	}
+}
+
+func ruleDescription(r ContextRule) string {
+	if s, ok := r.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+func reportTypeName(pkg string, typeName string) string {
+	if pkg == "" {
+		return typeName
+	}
+	return pkg + "." + typeName
+}
+
+func (s *scraper) reportVisit(pkg string, typeName string) {
+	if s.report == nil {
+		return
+	}
+	s.report.VisitedTypes[reportTypeName(pkg, typeName)]++
+}
+
+func (s *scraper) reportSkippedPackage(pkg string) {
+	if s.report == nil {
+		return
+	}
+	s.report.SkippedPackages[pkg]++
+}
+
+func (s *scraper) reportUnmatched(pkg string, typeName string) {
+	if s.report == nil {
+		return
+	}
+	s.report.UnmatchedTypes[reportTypeName(pkg, typeName)]++
+}
+
+func (s *scraper) reportRecursionCutoff(pkg string, typeName string) {
+	if s.report == nil {
+		return
+	}
+	s.report.RecursionCutoffs[reportTypeName(pkg, typeName)]++
+}
+
+func (s *scraper) reportRuleHit(i int) {
+	if s.report == nil {
+		return
+	}
+	s.report.Rules[i].Hits++
+}
+
+// withReport runs the scraping function collecting the report.
+func (s *scraper) withReport(scrape func()) Report {
+	s.report = newReport(s.rules)
+
+	start := time.Now()
+	scrape()
+	s.report.Elapsed = time.Since(start)
+
+	return *s.report
+}
@@ -1,7 +1,9 @@
 package scraper
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
 	"github.com/pkg/errors"
@@ -22,6 +24,18 @@ type RuleApplyFunc func(
 	groups ...string,
 ) model.Info
 
+// RuleContextApplyFunc defines the signature for a function that returns
+// component information of type `model.Info` based on the full context
+// of the matched type.
+//
+// Arguments:
+// - ctx: The context of the matched type, see `MatchContext`
+// - groups: A slice of sub-groups extracted from the rule's name regular expression
+type RuleContextApplyFunc func(
+	ctx MatchContext,
+	groups ...string,
+) model.Info
+
 // Rule defines an interface for rules that can be registered with the scraper.
 //
 // Applies determines if the rule should be applied to a given component
@@ -38,16 +52,79 @@ type Rule interface {
 	) model.Info
 }
 
+// ContextRule defines an interface for rules resolving component information
+// from the full context of the matched type, e.g. its parent component
+// or the struct field it has been reached through.
+//
+// Matches determines if the rule should be applied to the type described
+// by the context.
+// ApplyContext returns component information of type `model.Info` based on
+// the context of the type.
+//
+// Rules implementing only the Rule interface are adapted with AdaptRule.
+type ContextRule interface {
+	Matches(
+		ctx MatchContext,
+	) bool
+	ApplyContext(
+		ctx MatchContext,
+	) model.Info
+}
+
+// AdaptRule returns a ContextRule applying the given Rule.
+//
+// Unless the rule implements ContextRule itself, it is applied to structs
+// and interfaces matching its package and name, and receives the name
+// of the type only. Rules matching types on more than their package
+// and name implement ContextRule, as the default Rule implementation does.
+func AdaptRule(r Rule) ContextRule {
+	if cr, ok := r.(ContextRule); ok {
+		return cr
+	}
+	return ruleAdapter{rule: r}
+}
+
+type ruleAdapter struct {
+	rule Rule
+}
+
+func (a ruleAdapter) Matches(ctx MatchContext) bool {
+	return isComponentKind(ctx.Kind) && a.rule.Applies(ctx.Pkg, ctx.Name)
+}
+
+func (a ruleAdapter) ApplyContext(ctx MatchContext) model.Info {
+	return a.rule.Apply(ctx.Name)
+}
+
+func (a ruleAdapter) String() string {
+	if s, ok := a.rule.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", a.rule)
+}
+
+func adaptRules(rules []Rule) []ContextRule {
+	adapted := make([]ContextRule, len(rules))
+	for i, r := range rules {
+		adapted[i] = AdaptRule(r)
+	}
+	return adapted
+}
+
 type rule struct {
-	pkgRegexes []*regexp.Regexp
-	nameRegex  *regexp.Regexp
-	applyFunc  RuleApplyFunc
+	pkgRegexes       []*regexp.Regexp
+	nameRegex        *regexp.Regexp
+	matcher          Matcher
+	applyFunc        RuleApplyFunc
+	contextApplyFunc RuleContextApplyFunc
 }
 
 func newRule(
 	pkgRegexes []*regexp.Regexp,
 	nameRegex *regexp.Regexp,
+	matcher Matcher,
 	applyFunc RuleApplyFunc,
+	contextApplyFunc RuleContextApplyFunc,
 ) (rule, error) {
 	if len(pkgRegexes) == 0 {
 		return rule{}, errors.New(
@@ -69,16 +146,24 @@ func newRule(
 		)
 	}
 
-	if applyFunc == nil {
+	if applyFunc == nil && contextApplyFunc == nil {
 		return rule{}, errors.New(
 			"applyFunc function must be defined",
 		)
 	}
 
+	if applyFunc != nil && contextApplyFunc != nil {
+		return rule{}, errors.New(
+			"only one of applyFunc and contextApplyFunc functions must be defined",
+		)
+	}
+
 	return rule{
-		pkgRegexes: pkgRegexes,
-		nameRegex:  nameRegex,
-		applyFunc:  applyFunc,
+		pkgRegexes:       pkgRegexes,
+		nameRegex:        nameRegex,
+		matcher:          matcher,
+		applyFunc:        applyFunc,
+		contextApplyFunc: contextApplyFunc,
 	}, nil
 }
 
@@ -95,23 +180,69 @@ func (r rule) Applies(
 	return r.nameApplies(name) && r.pkgApplies(pkg)
 }
 
+// Matches determines if the rule should be applied to the type described
+// by the context.
+//
+// Rules without a matcher are applied to structs and interfaces matching
+// both the package and the name expressions. Rules with a matcher are applied
+// to named types of any kind, e.g. functions, matching both expressions
+// and the matcher.
+func (r rule) Matches(ctx MatchContext) bool {
+	if !r.Applies(ctx.Pkg, ctx.Name) {
+		return false
+	}
+	if r.matcher == nil {
+		return isComponentKind(ctx.Kind)
+	}
+	return r.matcher.Matches(ctx)
+}
+
+// String returns the description of the rule.
+func (r rule) String() string {
+	pkgs := make([]string, len(r.pkgRegexes))
+	for i, rgx := range r.pkgRegexes {
+		pkgs[i] = rgx.String()
+	}
+	return fmt.Sprintf("rule: pkg_regexps: [%s], name_regexp: %s",
+		strings.Join(pkgs, ", "), r.nameRegex)
+}
+
 // Apply returns component information of type `model.Info` based on
 // the type name in the format `package.TypeName`.
 //
 // Apply invokes the registered `RuleApplyFunc`, passing the following arguments:
 // - name: The scraped name of the type in the format `package.TypeName`
 // - groups: A slice of sub-groups extracted from the rule's name regular expression
+//
+// If the rule has been built with a `RuleContextApplyFunc`, it is invoked
+// with the context holding the name only.
 func (r rule) Apply(
 	name string,
 ) model.Info {
-	regex := r.nameRegex
+	return r.ApplyContext(MatchContext{Name: name})
+}
 
-	groups := regex.FindAllStringSubmatch(name, -1)
-	if len(groups) != 0 && len(groups[0]) > 1 {
-		return r.applyFunc(groups[0][0], groups[0][1:]...)
+// ApplyContext returns component information of type `model.Info` based on
+// the context of the type.
+//
+// ApplyContext invokes the registered `RuleContextApplyFunc` with the context
+// and the sub-groups extracted from the rule's name regular expression,
+// or the registered `RuleApplyFunc` the same way Apply does.
+func (r rule) ApplyContext(
+	ctx MatchContext,
+) model.Info {
+	name := ctx.Name
+	var groups []string
+
+	matches := r.nameRegex.FindAllStringSubmatch(name, -1)
+	if len(matches) != 0 && len(matches[0]) > 1 {
+		name, groups = matches[0][0], matches[0][1:]
 	}
 
-	return r.applyFunc(name)
+	if r.contextApplyFunc != nil {
+		return r.contextApplyFunc(ctx, groups...)
+	}
+	return r.applyFunc(name, groups...)
 }
 
 func (r rule) pkgApplies(pkg string) bool {
@@ -134,24 +265,33 @@ func (r rule) nameApplies(name string) bool {
 //
 // WithPkgRegexps sets the list of package regular expressions.
 // WithNameRegexp sets the name regular expression.
+// WithMatcher adds a matcher the types must be matched by,
+// e.g. `Implements(t)`. Multiple matchers are combined with And.
 // WithApplyFunc sets the rule application function (`RuleApplyFunc`).
+// WithContextApplyFunc sets the rule application function receiving
+// the full context of the type (`RuleContextApplyFunc`).
 //
 // Build returns a `Rule` implementation constructed from the provided
-// regular expressions and application function. It will return an error
-// if any of the provided expressions are invalid and cannot be compiled,
-// or if the application function (`RuleApplyFunc`) is missing.
+// regular expressions and application function. The implementation
+// implements ContextRule as well. It will return an error if any of
+// the provided expressions are invalid and cannot be compiled, or if
+// not exactly one of the application functions is provided.
 type RuleBuilder interface {
 	WithPkgRegexps(rgx ...string) RuleBuilder
 	WithNameRegexp(rgx string) RuleBuilder
+	WithMatcher(m Matcher) RuleBuilder
 	WithApplyFunc(f RuleApplyFunc) RuleBuilder
+	WithContextApplyFunc(f RuleContextApplyFunc) RuleBuilder
 
 	Build() (Rule, error)
 }
 
 type ruleBuilder struct {
-	pkgRegexes []string
-	nameRegex  string
-	applyFunc  RuleApplyFunc
+	pkgRegexes       []string
+	nameRegex        string
+	matchers         []Matcher
+	applyFunc        RuleApplyFunc
+	contextApplyFunc RuleContextApplyFunc
 }
 
 // NewRule returns a new, empty RuleBuilder.
@@ -171,12 +311,28 @@ func (b *ruleBuilder) WithNameRegexp(rgx string) RuleBuilder {
 	return b
 }
 
+// WithMatcher adds a matcher the types must be matched by.
+//
+// Multiple matchers are combined with And.
+func (b *ruleBuilder) WithMatcher(m Matcher) RuleBuilder {
+	if m != nil {
+		b.matchers = append(b.matchers, m)
+	}
+	return b
+}
+
 // WithApplyFunc sets rule application function RuleApplyFunc.
 func (b *ruleBuilder) WithApplyFunc(f RuleApplyFunc) RuleBuilder {
 	b.applyFunc = f
 	return b
 }
 
+// WithContextApplyFunc sets rule application function RuleContextApplyFunc.
+func (b *ruleBuilder) WithContextApplyFunc(f RuleContextApplyFunc) RuleBuilder {
+	b.contextApplyFunc = f
+	return b
+}
+
 // Build returns Rule implementation constructed from the provided expressions
 // and application function.
 //
@@ -185,7 +341,8 @@ func (b *ruleBuilder) WithApplyFunc(f RuleApplyFunc) RuleBuilder {
 //
 // Build will return an error if at least one of the provided expressions
 // is invalid and cannot be compiled.
-// Build will return an error if application function RuleApplyFunc is missing.
+// Build will return an error if neither application function RuleApplyFunc
+// nor RuleContextApplyFunc is provided, or if both of them are.
 func (b ruleBuilder) Build() (Rule, error) {
 	pkgRegexes := make([]*regexp.Regexp, 0)
 	for _, rgx := range b.pkgRegexes {
@@ -213,13 +370,28 @@ func (b ruleBuilder) Build() (Rule, error) {
 		nameRegex = r
 	}
 
-	if b.applyFunc == nil {
+	if b.applyFunc == nil && b.contextApplyFunc == nil {
 		return nil, errors.New("apply function must be provided")
 	}
 
+	if b.applyFunc != nil && b.contextApplyFunc != nil {
+		return nil, errors.New("only one of apply functions must be provided")
+	}
+
+	var matcher Matcher
+	switch len(b.matchers) {
+	case 0:
+	case 1:
+		matcher = b.matchers[0]
+	default:
+		matcher = And(b.matchers...)
+	}
+
 	return newRule(
 		pkgRegexes,
 		nameRegex,
+		matcher,
 		b.applyFunc,
+		b.contextApplyFunc,
 	)
 }
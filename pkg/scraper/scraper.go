@@ -1,7 +1,11 @@
 package scraper
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"reflect"
+	"sync"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
 	"github.com/krzysztofreczek/go-structurizr/pkg/yaml"
@@ -14,34 +18,133 @@ import (
 // and registered rules. It returns an open `model.Structure` containing recognized
 // components and the relationships between them.
 //
+// TryScrape works the same way as Scrape, but additionally returns
+// the `Errors` encountered while scraping, e.g. component ID collisions
+// or panics of `Info()` methods and rules recovered as `PanicError`.
+// Elements affected by the errors are omitted from the returned structure.
+//
+// ScrapeContext works the same way as TryScrape, but stops scraping once
+// the context is done, returning the structure scraped so far and the context
+// error among the `Errors`.
+//
+// ScrapeWithReport works the same way as TryScrape, but additionally returns
+// the `Report` describing the scraping process, e.g. types that have not been
+// matched by any rule and hit counts of the rules.
+//
+// ScrapeAll works the same way as TryScrape, but scrapes multiple roots
+// merging them into a single structure.
+//
 // RegisterRule registers a `Rule` with the scraper. It will return an error
 // if the provided rule is nil.
+//
+// RegisterContextRule registers a `ContextRule` with the scraper. It will return
+// an error if the provided rule is nil.
+//
+// RegisterIgnoreRule registers an `IgnoreRule` with the scraper. It will return
+// an error if the provided rule is nil.
+//
+// OnComponent, OnRelation and OnVisit register hooks called for each component,
+// relation and visited value respectively. They will return an error if
+// the provided hook is nil.
+//
+// Each call scrapes into a new structure, independent of the previous calls.
+// The scraper is safe for concurrent use by multiple goroutines. Rules and
+// hooks registered while scraping apply to subsequent calls only.
 type Scraper interface {
 	Scrape(i interface{}) model.Structure
+	TryScrape(i interface{}) (model.Structure, error)
+	ScrapeContext(ctx context.Context, i interface{}) (model.Structure, error)
+	ScrapeWithReport(i interface{}) (model.Structure, Report, error)
+	ScrapeAll(roots ...interface{}) (model.Structure, error)
 	RegisterRule(r Rule) error
+	RegisterContextRule(r ContextRule) error
+	RegisterIgnoreRule(r IgnoreRule) error
+	OnComponent(h ComponentHook) error
+	OnRelation(h RelationHook) error
+	OnVisit(h VisitHook) error
 }
 
+// scraper holds the configuration and the rules, as well as the state
+// of a single scraping run. Each call of the public methods scrapes
+// with a new run created by newRun, so that calls are independent.
 type scraper struct {
+	mu           sync.RWMutex
 	config       Configuration
-	rules        []Rule
+	rules        []ContextRule
+	ignoreRules  []IgnoreRule
 	structure    model.Structure
 	typeCounters map[string]int
+	componentIDs map[string]string
+	collisions   map[string]struct{}
+	hooked       map[string]bool
+	errs         []error
+	report       *Report
+	logger       *slog.Logger
+	position     position
+	ctx          context.Context
+	aborted      bool
+	limitsHit    map[Limit]struct{}
+
+	// componentParents holds the parent components the components
+	// have been first reached from, see ancestorTypeName.
+	componentParents map[string]string
+
+	// propagatePanics disables recovering panics of the user code,
+	// see recoverPanic.
+	propagatePanics bool
+
+	componentHooks []ComponentHook
+	relationHooks  []RelationHook
+	visitHooks     []VisitHook
 }
 
-// NewScraper creates a new Scraper instance using the provided Configuration.
-func NewScraper(config Configuration) Scraper {
+func newScraper(config Configuration, rules []ContextRule, ignoreRules []IgnoreRule) *scraper {
 	return &scraper{
 		config:       config,
-		rules:        make([]Rule, 0),
+		rules:        rules,
+		ignoreRules:  ignoreRules,
 		structure:    model.NewStructure(),
 		typeCounters: make(map[string]int),
+		componentIDs: make(map[string]string),
+		collisions:   make(map[string]struct{}),
+		hooked:       make(map[string]bool),
+		logger:       newLogger(config.Logger),
+		ctx:          context.Background(),
+		limitsHit:    make(map[Limit]struct{}),
+
+		componentParents: make(map[string]string),
 	}
 }
 
+// newRun returns a scraper with the configuration and the rules registered
+// so far, and a fresh scraping state.
+func (s *scraper) newRun() *scraper {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	run := newScraper(
+		s.config,
+		append([]ContextRule(nil), s.rules...),
+		append([]IgnoreRule(nil), s.ignoreRules...),
+	)
+	run.logger = s.logger
+	run.componentHooks = append([]ComponentHook(nil), s.componentHooks...)
+	run.relationHooks = append([]RelationHook(nil), s.relationHooks...)
+	run.visitHooks = append([]VisitHook(nil), s.visitHooks...)
+	return run
+}
+
+// NewScraper creates a new Scraper instance using the provided Configuration.
+func NewScraper(config Configuration) Scraper {
+	return newScraper(config, make([]ContextRule, 0), make([]IgnoreRule, 0))
+}
+
 // NewScraperFromConfigFile creates a new Scraper instance using Configuration
 // loaded from the specified YAML configuration file.
 //
-// It returns an error if the YAML file does not exist or contains invalid content.
+// It returns an error if the YAML file does not exist or contains invalid content,
+// including rules with the `implements` condition, which is supported
+// by the static scraper only.
 func NewScraperFromConfigFile(fileName string) (Scraper, error) {
 	configuration, err := yaml.LoadFromFile(fileName)
 	if err != nil {
@@ -49,19 +152,31 @@ func NewScraperFromConfigFile(fileName string) (Scraper, error) {
 			"could not load configuration from file `%s`", fileName)
 	}
 
-	config := toScraperConfig(configuration)
+	config, err := toScraperConfig(configuration)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load scraper configuration from file `%s`", fileName)
+	}
+
+	err = checkRuntimeRules(configuration)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load scraper rules from file `%s`", fileName)
+	}
+
 	rules, err := toScraperRules(configuration)
 	if err != nil {
 		return nil, errors.Wrapf(err,
 			"could not load scraper rules from file `%s`", fileName)
 	}
 
-	return &scraper{
-		config:       config,
-		rules:        rules,
-		structure:    model.NewStructure(),
-		typeCounters: make(map[string]int),
-	}, nil
+	ignoreRules, err := toScraperIgnoreRules(configuration)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load scraper ignore rules from file `%s`", fileName)
+	}
+
+	return newScraper(config, adaptRules(rules), ignoreRules), nil
 }
 
 // RegisterRule adds the specified Rule to the scraper.
@@ -71,6 +186,21 @@ func (s *scraper) RegisterRule(r Rule) error {
 	if r == nil {
 		return errors.New("rule must not be nil")
 	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = append(s.rules, AdaptRule(r))
+	return nil
+}
+
+// RegisterContextRule adds the specified ContextRule to the scraper.
+//
+// It returns an error if the provided rule is nil.
+func (s *scraper) RegisterContextRule(r ContextRule) error {
+	if r == nil {
+		return errors.New("rule must not be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.rules = append(s.rules, r)
 	return nil
 }
@@ -79,9 +209,70 @@ func (s *scraper) RegisterRule(r Rule) error {
 // and registered rules.
 //
 // It returns an open `model.Structure` containing recognized components
-// and their relationships.
+// and their relationships. Panics of the user code called while scraping
+// are not recovered. Errors encountered while scraping, e.g. component ID
+// collisions, are only logged at the warning level with the configured
+// logger. Use TryScrape or ScrapeWithReport to get them instead.
 func (s *scraper) Scrape(i interface{}) model.Structure {
+	run := s.newRun()
+	run.propagatePanics = true
+	run.scrapeRoot(i)
+	run.logErrors()
+	return run.structure
+}
+
+func (s *scraper) scrapeRoot(i interface{}) {
+	defer s.recoverPanic(fmt.Sprintf("%T", i), "scraping", "")
+
 	v := reflect.ValueOf(i)
-	s.scrape(v, "", 0)
-	return s.structure
+	s.scrape(v, origin{}, 0)
+}
+
+// TryScrape processes the given structure according to the internal
+// configuration and registered rules.
+//
+// It returns an open `model.Structure` containing recognized components
+// and their relationships, and the `Errors` encountered while scraping.
+func (s *scraper) TryScrape(i interface{}) (model.Structure, error) {
+	run := s.newRun()
+	run.scrapeRoot(i)
+	return run.structure, run.err()
+}
+
+// ScrapeAll processes the given roots according to the internal
+// configuration and registered rules, merging them into a single structure.
+//
+// Components used by multiple roots are scraped once, with the relations
+// of each of the roots.
+// It returns the merged `model.Structure` and the `Errors` encountered
+// while scraping.
+func (s *scraper) ScrapeAll(roots ...interface{}) (model.Structure, error) {
+	run := s.newRun()
+	for _, i := range roots {
+		run.scrapeRoot(i)
+	}
+	return run.structure, run.err()
+}
+
+// ScrapeContext works the same way as TryScrape, but stops scraping
+// once the context is done.
+//
+// It returns the structure scraped so far and the `Errors` encountered
+// while scraping, including the context error if the context is done.
+func (s *scraper) ScrapeContext(ctx context.Context, i interface{}) (model.Structure, error) {
+	run := s.newRun()
+	run.withContext(ctx, func() {
+		run.scrapeRoot(i)
+	})
+	return run.structure, run.err()
+}
+
+// ScrapeWithReport works the same way as TryScrape, but additionally
+// returns the `Report` describing the scraping process.
+func (s *scraper) ScrapeWithReport(i interface{}) (model.Structure, Report, error) {
+	run := s.newRun()
+	report := run.withReport(func() {
+		run.scrapeRoot(i)
+	})
+	return run.structure, report, run.err()
 }
@@ -1,7 +1,17 @@
 package scraper_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sync"
 	"testing"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/internal"
@@ -1087,6 +1097,1343 @@ func TestScraper_Scrape_rules(t *testing.T) {
 	}
 }
 
+func TestScraper_Scrape_relations(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	var tests = []struct {
+		name             string
+		structure        interface{}
+		expectedRelation model.Relation
+	}{
+		{
+			name:      "field",
+			structure: test.NewRootHasInfoWithNestedComponents(),
+			expectedRelation: model.Relation{
+				SourceID: componentID("RootHasInfoWithNestedComponents"),
+				TargetID: componentID("RootHasInfoWithComponentHasInfoPointer"),
+				Path:     "RootHasInfoWithNestedComponents.SubRoot",
+				Kind:     model.RelationKindField,
+			},
+		},
+		{
+			name:      "pointer field",
+			structure: test.NewRootHasInfoWithComponentHasInfoPointer(),
+			expectedRelation: model.Relation{
+				SourceID: componentID("RootHasInfoWithComponentHasInfoPointer"),
+				TargetID: componentID("PublicComponentHasInfo"),
+				Path:     "RootHasInfoWithComponentHasInfoPointer.Ptr",
+				Kind:     model.RelationKindField,
+			},
+		},
+		{
+			name:      "slice element",
+			structure: test.NewRootHasInfoWithCircularDependencies(),
+			expectedRelation: model.Relation{
+				SourceID: componentID("RootHasInfoWithCircularDependencies"),
+				TargetID: componentID("RootHasInfoWithCircularDependencies"),
+				Path:     "RootHasInfoWithCircularDependencies.nested[]",
+				Kind:     model.RelationKindSliceElement,
+			},
+		},
+		{
+			name:      "method argument",
+			structure: test.NewRootWithPublicMethodWithHasInfoArgument(),
+			expectedRelation: model.Relation{
+				SourceID: componentID("RootWithPublicMethodWithHasInfoArgument"),
+				TargetID: componentID("RootEmptyHasInfo"),
+				Path:     "RootWithPublicMethodWithHasInfoArgument.M()",
+				Kind:     model.RelationKindMethodArg,
+			},
+		},
+		{
+			name:      "method return type",
+			structure: test.NewRootWithPublicMethodWithHasInfoReturnType(),
+			expectedRelation: model.Relation{
+				SourceID: componentID("RootWithPublicMethodWithHasInfoReturnType"),
+				TargetID: componentID("RootEmptyHasInfo"),
+				Path:     "RootWithPublicMethodWithHasInfoReturnType.M()",
+				Kind:     model.RelationKindMethodReturn,
+			},
+		},
+		{
+			name:      "explicit relation",
+			structure: test.NewRootHasInfoWithRelations(),
+			expectedRelation: model.Relation{
+				SourceID:   componentID("RootHasInfoWithRelations"),
+				TargetID:   externalComponentID("test.Database"),
+				Label:      "reads and writes",
+				Kind:       model.RelationKindExplicit,
+				Technology: "SQL",
+				Tags:       []string{},
+			},
+		},
+		{
+			name:      "explicit relation literal",
+			structure: test.NewRootHasInfoWithRelations(),
+			expectedRelation: model.Relation{
+				SourceID: componentID("RootHasInfoWithRelations"),
+				TargetID: externalComponentID("test.Queue"),
+				Label:    "publishes events",
+				Kind:     model.RelationKindExplicit,
+				Tags:     []string{"ASYNC"},
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := scraper.NewScraper(c)
+			result := s.Scrape(tt.structure)
+			r := tt.expectedRelation
+			require.Equal(t, r, result.Relations[r.SourceID][r.TargetID])
+		})
+	}
+}
+
+func TestScraper_Scrape_generics(t *testing.T) {
+	ruleMatchBaseName, err := scraper.NewRule().
+		WithNameRegexp(`^test\.GenericRepository$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	ruleMatchInstantiation, err := scraper.NewRule().
+		WithNameRegexp(`^test\.GenericRepository\[PublicComponent\]$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name               string
+		generics           scraper.GenericsMode
+		rule               scraper.Rule
+		expectedComponents map[string]string
+	}{
+		{
+			name:     "separate instantiations matched by base name",
+			generics: scraper.GenericsSeparate,
+			rule:     ruleMatchBaseName,
+			expectedComponents: map[string]string{
+				componentID("RootWithGenericRepositories"):                            "test.RootWithGenericRepositories",
+				componentIDf("GenericRepository[%s.PublicComponent]", testPKG):        "test.GenericRepository",
+				componentIDf("GenericRepository[%s.PublicComponentHasInfo]", testPKG): "test.GenericRepository",
+				componentID("PublicComponentHasInfo"):                                 "test.PublicComponentHasInfo",
+			},
+		},
+		{
+			name:     "separate instantiations matched by short name",
+			generics: scraper.GenericsSeparate,
+			rule:     ruleMatchInstantiation,
+			expectedComponents: map[string]string{
+				componentID("RootWithGenericRepositories"):                     "test.RootWithGenericRepositories",
+				componentIDf("GenericRepository[%s.PublicComponent]", testPKG): "test.GenericRepository[PublicComponent]",
+				componentID("PublicComponentHasInfo"):                          "test.PublicComponentHasInfo",
+			},
+		},
+		{
+			name:     "merged instantiations",
+			generics: scraper.GenericsMerge,
+			rule:     ruleMatchBaseName,
+			expectedComponents: map[string]string{
+				componentID("RootWithGenericRepositories"): "test.RootWithGenericRepositories",
+				componentID("GenericRepository"):           "test.GenericRepository",
+				componentID("PublicComponentHasInfo"):      "test.PublicComponentHasInfo",
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+			c.Generics = tt.generics
+
+			s := scraper.NewScraper(c)
+			err := s.RegisterRule(tt.rule)
+			require.NoError(t, err)
+
+			result := s.Scrape(test.NewRootWithGenericRepositories())
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+		})
+	}
+}
+
+func TestScraper_Scrape_anonymous_structs(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.RootHasInfoWithAnonymousStructs\.nested\.inner$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	err = s.RegisterRule(r)
+	require.NoError(t, err)
+
+	result := s.Scrape(test.NewRootHasInfoWithAnonymousStructs())
+
+	expectedComponents := map[string]string{
+		componentID("RootHasInfoWithAnonymousStructs"):              "test.RootHasInfoWithAnonymousStructs",
+		componentID("RootHasInfoWithAnonymousStructs.nested.inner"): "test.RootHasInfoWithAnonymousStructs.nested.inner",
+		componentID("PublicComponentHasInfo"):                       "test.PublicComponentHasInfo",
+	}
+	requireEqualComponentNames(t, expectedComponents, result.Components)
+
+	expectedRelations := []model.Relation{
+		{
+			SourceID: componentID("RootHasInfoWithAnonymousStructs"),
+			TargetID: componentID("PublicComponentHasInfo"),
+			Path:     "RootHasInfoWithAnonymousStructs.config.Component",
+			Kind:     model.RelationKindField,
+		},
+		{
+			SourceID: componentID("RootHasInfoWithAnonymousStructs"),
+			TargetID: componentID("RootHasInfoWithAnonymousStructs.nested.inner"),
+			Path:     "RootHasInfoWithAnonymousStructs.nested.inner",
+			Kind:     model.RelationKindField,
+		},
+	}
+	for _, r := range expectedRelations {
+		require.Equal(t, r, result.Relations[r.SourceID][r.TargetID])
+	}
+}
+
+var matcherTests = []struct {
+	name               string
+	matcher            scraper.Matcher
+	expectedComponents map[string]string
+	expectedRelations  map[string][]string
+}{
+	{
+		name:    "kind",
+		matcher: scraper.KindOf(reflect.Func),
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithMatchedFields"): "test.RootHasInfoWithMatchedFields",
+			componentID("PublicHandlerFunc"):            "test.PublicHandlerFunc",
+			componentID("PublicComponentHasInfo"):       "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithMatchedFields"): {
+				componentID("PublicHandlerFunc"),
+			},
+			componentID("PublicHandlerFunc"): {
+				componentID("PublicComponentHasInfo"),
+			},
+		},
+	},
+	{
+		name:    "implements",
+		matcher: scraper.Implements(reflect.TypeOf((*test.PublicCloser)(nil)).Elem()),
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithMatchedFields"): "test.RootHasInfoWithMatchedFields",
+			componentID("PublicClosingComponent"):       "test.PublicClosingComponent",
+			componentID("PublicComponentHasInfo"):       "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithMatchedFields"): {
+				componentID("PublicClosingComponent"),
+				componentID("PublicComponentHasInfo"),
+			},
+		},
+	},
+	{
+		name:    "method",
+		matcher: scraper.And(scraper.HasMethod("Close"), scraper.Not(scraper.KindOf(reflect.Interface))),
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithMatchedFields"): "test.RootHasInfoWithMatchedFields",
+			componentID("PublicClosingComponent"):       "test.PublicClosingComponent",
+			componentID("PublicComponentHasInfo"):       "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithMatchedFields"): {
+				componentID("PublicClosingComponent"),
+				componentID("PublicComponentHasInfo"),
+			},
+		},
+	},
+	{
+		name:    "struct tag",
+		matcher: scraper.HasStructTag("role"),
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithMatchedFields"): "test.RootHasInfoWithMatchedFields",
+			componentID("PublicComponent"):              "test.PublicComponent",
+			componentID("PublicComponentHasInfo"):       "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithMatchedFields"): {
+				componentID("PublicComponent"),
+				componentID("PublicComponentHasInfo"),
+			},
+		},
+	},
+	{
+		name:    "any",
+		matcher: scraper.Or(scraper.HasStructTag("role"), scraper.KindOf(reflect.Func)),
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithMatchedFields"): "test.RootHasInfoWithMatchedFields",
+			componentID("PublicHandlerFunc"):            "test.PublicHandlerFunc",
+			componentID("PublicComponent"):              "test.PublicComponent",
+			componentID("PublicComponentHasInfo"):       "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithMatchedFields"): {
+				componentID("PublicHandlerFunc"),
+				componentID("PublicComponent"),
+			},
+			componentID("PublicHandlerFunc"): {
+				componentID("PublicComponentHasInfo"),
+			},
+		},
+	},
+}
+
+func TestScraper_Scrape_rule_matchers(t *testing.T) {
+	for _, tt := range matcherTests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+
+			r, err := scraper.NewRule().
+				WithMatcher(tt.matcher).
+				WithApplyFunc(func(name string, groups ...string) model.Info {
+					return model.ComponentInfo(name)
+				}).
+				Build()
+			require.NoError(t, err)
+
+			s := scraper.NewScraper(c)
+			require.NoError(t, s.RegisterRule(r))
+
+			result := s.Scrape(test.NewRootHasInfoWithMatchedFields())
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+			requireEqualRelations(t, tt.expectedRelations, result.Relations)
+		})
+	}
+}
+
+func TestScraper_Scrape_rules_without_matchers_skip_other_kinds(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.PublicHandlerFunc$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	result := s.Scrape(test.NewRootHasInfoWithMatchedFields())
+	requireEqualComponentNames(t, map[string]string{
+		componentID("RootHasInfoWithMatchedFields"): "test.RootHasInfoWithMatchedFields",
+		componentID("PublicComponentHasInfo"):       "test.PublicComponentHasInfo",
+	}, result.Components)
+}
+
+func TestScraper_Scrape_context_rules(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	var contexts []scraper.MatchContext
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.Public(\w*)Component$`).
+		WithContextApplyFunc(func(ctx scraper.MatchContext, groups ...string) model.Info {
+			contexts = append(contexts, ctx)
+			return model.Info{
+				Name:        ctx.Name,
+				Description: fmt.Sprintf("%s %s used by %s", groups[0], ctx.Field, ctx.Parent.Name),
+				Technology:  ctx.Type.String(),
+			}
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.RegisterContextRule(r.(scraper.ContextRule)))
+
+	result := s.Scrape(test.NewRootHasInfoWithMatchedFields())
+
+	closer := result.Components[componentID("PublicClosingComponent")]
+	require.Equal(t, "Closing Closer used by test.RootHasInfoWithMatchedFields", closer.Description)
+	require.Equal(t, "test.PublicClosingComponent", closer.Technology)
+
+	store := result.Components[componentID("PublicComponent")]
+	require.Equal(t, " Store used by test.RootHasInfoWithMatchedFields", store.Description)
+
+	require.Len(t, contexts, 2)
+	require.Equal(t, "Closer", contexts[0].Field)
+	require.Equal(t, reflect.Struct, contexts[0].Kind)
+	require.Equal(t, 1, contexts[0].Level)
+	require.Equal(t, componentID("RootHasInfoWithMatchedFields"), contexts[0].Parent.ID)
+	require.Equal(t, reflect.StructTag(`role:"database"`), contexts[1].Tag)
+}
+
+var annotatedComponents = map[string]model.Component{
+	componentID("RootHasInfoWithAnnotatedFields"): {
+		ID:          componentID("RootHasInfoWithAnnotatedFields"),
+		Kind:        "component",
+		Name:        "test.RootHasInfoWithAnnotatedFields",
+		Description: "public",
+		Tags:        []string{},
+	},
+	componentID("PublicComponent@RootHasInfoWithAnnotatedFields.Orders"): {
+		ID:         componentID("PublicComponent@RootHasInfoWithAnnotatedFields.Orders"),
+		Kind:       "component",
+		Name:       "Orders DB",
+		Technology: "PostgreSQL",
+		Tags:       []string{"DB", "SQL"},
+	},
+	componentID("PublicComponent@RootHasInfoWithAnnotatedFields.Payments"): {
+		ID:         componentID("PublicComponent@RootHasInfoWithAnnotatedFields.Payments"),
+		Kind:       "component",
+		Name:       "Payments DB",
+		Technology: "MySQL",
+		Tags:       []string{},
+	},
+	componentID("PublicComponentHasInfo@RootHasInfoWithAnnotatedFields.Component"): {
+		ID:          componentID("PublicComponentHasInfo@RootHasInfoWithAnnotatedFields.Component"),
+		Kind:        "component",
+		Name:        "test.PublicComponentHasInfo",
+		Description: "annotated",
+		Tags:        []string{},
+	},
+	internal.Hash("time.Location@RootHasInfoWithAnnotatedFields.Location"): {
+		ID:   internal.Hash("time.Location@RootHasInfoWithAnnotatedFields.Location"),
+		Kind: "component",
+		Name: "Location",
+		Tags: []string{},
+	},
+}
+
+func TestScraper_TryScrape_annotations(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	result, err := scraper.NewScraper(c).TryScrape(test.NewRootHasInfoWithAnnotatedFields())
+
+	var errs scraper.Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 1)
+
+	var annotationErr scraper.AnnotationError
+	require.ErrorAs(t, errs[0], &annotationErr)
+	require.Equal(t, "RootHasInfoWithAnnotatedFields.Invalid", annotationErr.Path)
+	require.Equal(t, "name", annotationErr.Tag)
+
+	requireEqualComponents(t, annotatedComponents, result.Components)
+
+	root := componentID("RootHasInfoWithAnnotatedFields")
+	require.Len(t, result.Relations[root], 4)
+}
+
+var annotatedNonStructComponents = map[string]model.Component{
+	componentID("RootHasInfoWithAnnotatedNonStructFields"): {
+		ID:          componentID("RootHasInfoWithAnnotatedNonStructFields"),
+		Kind:        "component",
+		Name:        "test.RootHasInfoWithAnnotatedNonStructFields",
+		Description: "public",
+		Tags:        []string{},
+	},
+	componentID("PublicInterface@RootHasInfoWithAnnotatedNonStructFields.Store"): {
+		ID:         componentID("PublicInterface@RootHasInfoWithAnnotatedNonStructFields.Store"),
+		Kind:       "component",
+		Name:       "Orders DB",
+		Technology: "PostgreSQL",
+		Tags:       []string{},
+	},
+	componentID("PublicNamedOptions@RootHasInfoWithAnnotatedNonStructFields.Options"): {
+		ID:   componentID("PublicNamedOptions@RootHasInfoWithAnnotatedNonStructFields.Options"),
+		Kind: "component",
+		Name: "Options",
+		Tags: []string{},
+	},
+}
+
+func TestScraper_TryScrape_annotated_non_struct_fields(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	result, err := scraper.NewScraper(c).TryScrape(test.NewRootHasInfoWithAnnotatedNonStructFields())
+	require.NoError(t, err)
+	requireEqualComponents(t, annotatedNonStructComponents, result.Components)
+}
+
+var instancesTests = []struct {
+	name               string
+	mode               scraper.InstancesMode
+	expectedComponents map[string]string
+	expectedRelations  map[string][]string
+}{
+	{
+		name: "merge",
+		mode: scraper.InstancesMerge,
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithInstances"): "test.RootHasInfoWithInstances",
+			componentID("PublicRepository"):         "test.PublicRepository",
+			componentID("PublicNamedRepository"):    "test.PublicNamedRepository",
+			componentID("PublicComponentHasInfo"):   "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithInstances"): {
+				componentID("PublicRepository"),
+				componentID("PublicNamedRepository"),
+			},
+			componentID("PublicRepository"): {
+				componentID("PublicComponentHasInfo"),
+			},
+			componentID("PublicNamedRepository"): {
+				componentID("PublicComponentHasInfo"),
+			},
+		},
+	},
+	{
+		name: "separate",
+		mode: scraper.InstancesSeparate,
+		expectedComponents: map[string]string{
+			componentID("RootHasInfoWithInstances"):                                                         "test.RootHasInfoWithInstances",
+			componentID("PublicRepository@RootHasInfoWithInstances.Orders"):                                 "test.PublicRepository",
+			componentID("PublicRepository@RootHasInfoWithInstances.Payments"):                               "test.PublicRepository",
+			componentID("PublicNamedRepository@users"):                                                      "test.PublicNamedRepository",
+			componentID("PublicComponentHasInfo@RootHasInfoWithInstances.Orders/PublicRepository.Client"):   "test.PublicComponentHasInfo",
+			componentID("PublicComponentHasInfo@RootHasInfoWithInstances.Payments/PublicRepository.Client"): "test.PublicComponentHasInfo",
+			componentID("PublicComponentHasInfo@users/PublicNamedRepository.Client"):                        "test.PublicComponentHasInfo",
+		},
+		expectedRelations: map[string][]string{
+			componentID("RootHasInfoWithInstances"): {
+				componentID("PublicRepository@RootHasInfoWithInstances.Orders"),
+				componentID("PublicRepository@RootHasInfoWithInstances.Payments"),
+				componentID("PublicNamedRepository@users"),
+			},
+			componentID("PublicRepository@RootHasInfoWithInstances.Orders"): {
+				componentID("PublicComponentHasInfo@RootHasInfoWithInstances.Orders/PublicRepository.Client"),
+			},
+			componentID("PublicRepository@RootHasInfoWithInstances.Payments"): {
+				componentID("PublicComponentHasInfo@RootHasInfoWithInstances.Payments/PublicRepository.Client"),
+			},
+			componentID("PublicNamedRepository@users"): {
+				componentID("PublicComponentHasInfo@users/PublicNamedRepository.Client"),
+			},
+		},
+	},
+}
+
+func TestScraper_Scrape_instances(t *testing.T) {
+	for _, tt := range instancesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+			c.Instances = tt.mode
+
+			result, err := scraper.NewScraper(c).TryScrape(test.NewRootHasInfoWithInstances())
+			require.NoError(t, err)
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+			requireEqualRelations(t, tt.expectedRelations, result.Relations)
+		})
+	}
+}
+
+var recursiveInstancesComponents = map[string]string{
+	componentID("RootHasInfoWithRecursiveComponentHasInfo"):                                      "test.RootHasInfoWithRecursiveComponentHasInfo",
+	componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Head"): "test.PublicRecursiveComponentHasInfo",
+	componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Tail"): "test.PublicRecursiveComponentHasInfo",
+}
+
+var recursiveInstancesRelations = map[string][]string{
+	componentID("RootHasInfoWithRecursiveComponentHasInfo"): {
+		componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Head"),
+		componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Tail"),
+	},
+	componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Head"): {
+		componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Head"),
+	},
+	componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Tail"): {
+		componentID("PublicRecursiveComponentHasInfo@RootHasInfoWithRecursiveComponentHasInfo.Tail"),
+	},
+}
+
+func TestScraper_Scrape_instances_recursive(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.Instances = scraper.InstancesSeparate
+
+	result, err := scraper.NewScraper(c).TryScrape(test.NewRootHasInfoWithRecursiveComponentHasInfo())
+	require.NoError(t, err)
+	requireEqualComponentNames(t, recursiveInstancesComponents, result.Components)
+	requireEqualRelations(t, recursiveInstancesRelations, result.Relations)
+
+	result, err = scraper.NewScraper(c).TryScrape(test.NewRootHasInfoWithRecursiveComponent())
+	require.NoError(t, err)
+	require.Len(t, result.Components, 1)
+}
+
+func TestScraper_ScrapeWithReport(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r1, err := scraper.NewRule().
+		WithMatcher(scraper.KindOf(reflect.Func)).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	r2, err := scraper.NewRule().
+		WithNameRegexp(`^test\.Unknown$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.RegisterRule(r1))
+	require.NoError(t, s.RegisterRule(r2))
+
+	_, report, err := s.ScrapeWithReport(test.NewRootHasInfoWithMatchedFields())
+	require.NoError(t, err)
+
+	require.Contains(t, report.VisitedTypes, testPKG+".RootHasInfoWithMatchedFields")
+	require.Contains(t, report.VisitedTypes, testPKG+".PublicHandlerFunc")
+	require.Contains(t, report.SkippedPackages, "github.com/krzysztofreczek/go-structurizr/pkg/model")
+	require.Contains(t, report.UnmatchedTypes, testPKG+".PublicClosingComponent")
+	require.Contains(t, report.UnmatchedTypes, testPKG+".privateComponent")
+	require.NotContains(t, report.UnmatchedTypes, testPKG+".RootHasInfoWithMatchedFields")
+	require.Empty(t, report.RecursionCutoffs)
+
+	require.Len(t, report.Rules, 2)
+	require.Equal(t, 1, report.Rules[0].Hits)
+	require.Equal(t, []scraper.RuleReport{
+		{
+			Index: 1,
+			Rule:  "rule: pkg_regexps: [^.*$], name_regexp: ^test\\.Unknown$",
+		},
+	}, report.UnmatchedRules())
+	require.Contains(t, report.String(), "rules (2):")
+
+	_, report, err = scraper.NewScraper(c).ScrapeWithReport(test.NewRootHasInfoWithRecursiveComponent())
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{testPKG + ".PublicRecursiveComponent": 1}, report.RecursionCutoffs)
+}
+
+func TestScraper_TryScrape_limits(t *testing.T) {
+	var tests = []struct {
+		name                string
+		structure           interface{}
+		limits              scraper.Limits
+		expectedComponents  int
+		expectedVisits      int
+		expectedLimitErrors []scraper.LimitError
+	}{
+		{
+			name:               "no limits",
+			structure:          test.NewRootHasInfoWithManyElements(),
+			expectedComponents: 2,
+			expectedVisits:     10,
+		},
+		{
+			name:               "max elements",
+			structure:          test.NewRootHasInfoWithManyElements(),
+			limits:             scraper.Limits{MaxElements: 2},
+			expectedComponents: 2,
+			expectedVisits:     4,
+			expectedLimitErrors: []scraper.LimitError{
+				{Limit: scraper.LimitElements, Max: 2, Path: "RootHasInfoWithManyElements.Values"},
+			},
+		},
+		{
+			name:               "max depth",
+			structure:          test.NewRootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels(),
+			limits:             scraper.Limits{MaxDepth: 1},
+			expectedComponents: 3,
+			expectedLimitErrors: []scraper.LimitError{
+				{Limit: scraper.LimitDepth, Max: 1, Path: "PublicComponentHasInfo.DoSomethingPrivate"},
+			},
+		},
+		{
+			name:               "max components",
+			structure:          test.NewRootHasInfoWithManyElements(),
+			limits:             scraper.Limits{MaxComponents: 1},
+			expectedComponents: 1,
+			expectedVisits:     1,
+			expectedLimitErrors: []scraper.LimitError{
+				{Limit: scraper.LimitComponents, Max: 1, Path: "RootHasInfoWithManyElements.Values[]"},
+			},
+		},
+		{
+			name:               "max recursion",
+			structure:          test.NewRootHasInfoWithRecursiveComponent(),
+			limits:             scraper.Limits{MaxRecursion: 3},
+			expectedComponents: 1,
+			expectedLimitErrors: []scraper.LimitError{
+				{Limit: scraper.LimitRecursion, Max: 3, Path: "RootHasInfoWithRecursiveComponent.Chain.Next.Next.Next.Next"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+			c.Limits = tt.limits
+
+			s := scraper.NewScraper(c)
+			structure, report, err := s.ScrapeWithReport(tt.structure)
+			require.Len(t, structure.Components, tt.expectedComponents)
+
+			if tt.expectedVisits > 0 {
+				require.Equal(t, tt.expectedVisits, report.VisitedTypes[testPKG+".PublicComponentHasInfo"])
+			}
+
+			if len(tt.expectedLimitErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var errs scraper.Errors
+			require.ErrorAs(t, err, &errs)
+			require.Len(t, errs, len(tt.expectedLimitErrors))
+			for i, expected := range tt.expectedLimitErrors {
+				require.Equal(t, expected, errs[i])
+			}
+		})
+	}
+}
+
+func TestScraper_TryScrape_default_max_recursion(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	structure, report, err := scraper.NewScraper(c).ScrapeWithReport(test.NewRootHasInfoWithRecursiveComponent())
+	require.NoError(t, err)
+
+	c.Limits = scraper.Limits{MaxRecursion: 100}
+
+	limitedStructure, limitedReport, err := scraper.NewScraper(c).ScrapeWithReport(test.NewRootHasInfoWithRecursiveComponent())
+	require.Equal(t, structure, limitedStructure)
+	require.Equal(t, report.VisitedTypes, limitedReport.VisitedTypes)
+	require.Equal(t, report.RecursionCutoffs, limitedReport.RecursionCutoffs)
+	require.Equal(t, 102, report.VisitedTypes[testPKG+".PublicRecursiveComponent"])
+
+	var limitErr scraper.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, 100, limitErr.Max)
+}
+
+func TestScraper_ScrapeContext(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := scraper.NewScraper(c)
+	structure, err := s.ScrapeContext(ctx, test.NewRootHasInfoWithManyElements())
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Empty(t, structure.Components)
+
+	structure, err = scraper.NewScraper(c).ScrapeContext(context.Background(), test.NewRootHasInfoWithManyElements())
+	require.NoError(t, err)
+	require.Len(t, structure.Components, 2)
+}
+
+func TestScraper_TryScrape_panics(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.PublicComponentHasInfo$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			panic("rule failed")
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	structure, err := s.TryScrape(test.NewRootHasInfoWithPanickingComponent())
+	require.Error(t, err)
+
+	require.Contains(t, structure.Components, componentID("RootHasInfoWithPanickingComponent"))
+	require.Contains(t, structure.Components, componentID("PublicComponentHasInfo"))
+	require.NotContains(t, structure.Components, componentID("PublicPanickingComponent"))
+
+	var errs scraper.Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+
+	var infoPanic scraper.PanicError
+	require.ErrorAs(t, errs[0], &infoPanic)
+	require.Equal(t, "test.PublicPanickingComponent", infoPanic.Type)
+	require.Equal(t, "RootHasInfoWithPanickingComponent.Panicking", infoPanic.Path)
+	require.Equal(t, "Info()", infoPanic.Source)
+	require.Contains(t, infoPanic.Error(), "nil pointer dereference")
+
+	require.Equal(t, scraper.PanicError{
+		Type:   "test.PublicComponentHasInfo",
+		Path:   "RootHasInfoWithPanickingComponent.Value",
+		Source: "rule #0",
+		Value:  "rule failed",
+	}, errs[1])
+}
+
+func TestScraper_TryScrape_recovers_scraping_panics(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.IDStrategy = func(pkg string, name string) string {
+		panic("id strategy failed")
+	}
+
+	s := scraper.NewScraper(c)
+	structure, err := s.TryScrape(test.NewRootHasInfoWithPanickingComponent())
+	require.Empty(t, structure.Components)
+	require.Equal(t, scraper.Errors{
+		scraper.PanicError{
+			Type:   "test.RootHasInfoWithPanickingComponent",
+			Source: "scraping",
+			Value:  "id strategy failed",
+		},
+	}, err)
+}
+
+func TestScraper_Scrape_propagates_panics(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+	require.Panics(t, func() {
+		s.Scrape(test.NewRootHasInfoWithPanickingComponent())
+	})
+
+	_, err := s.TryScrape(test.NewRootHasInfoWithPanickingComponent())
+	require.Error(t, err)
+}
+
+func TestScraper_Scrape_isolated_calls(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+
+	first := s.Scrape(test.NewRootHasInfoWithManyElements())
+	second := s.Scrape(test.NewRootHasInfoWithCircularDependencies())
+
+	require.Contains(t, first.Components, componentID("RootHasInfoWithManyElements"))
+	require.NotContains(t, first.Components, componentID("RootHasInfoWithCircularDependencies"))
+	require.Contains(t, second.Components, componentID("RootHasInfoWithCircularDependencies"))
+	require.NotContains(t, second.Components, componentID("RootHasInfoWithManyElements"))
+
+	_, err := s.TryScrape(test.NewRootHasInfoWithManyElements())
+	require.NoError(t, err)
+}
+
+func TestScraper_ScrapeAll(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+	structure, err := s.ScrapeAll(
+		test.NewRootHasInfoWithManyElements(),
+		test.NewRootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels(),
+	)
+	require.NoError(t, err)
+
+	require.Contains(t, structure.Components, componentID("RootHasInfoWithManyElements"))
+	require.Contains(t, structure.Components, componentID("RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels"))
+
+	shared := componentID("PublicComponentHasInfo")
+	require.Contains(t, structure.Relations[componentID("RootHasInfoWithManyElements")], shared)
+	require.Contains(t, structure.Relations[componentID("RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels")], shared)
+}
+
+func TestScraper_Scrape_concurrent_calls(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+	expected := s.Scrape(test.NewRootHasInfoWithManyElements())
+
+	var wg sync.WaitGroup
+	results := make([]model.Structure, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			require.NoError(t, s.RegisterRule(nameOnlyRule{}))
+			results[i] = s.Scrape(test.NewRootHasInfoWithManyElements())
+		}(i)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		require.Equal(t, expected.Components, r.Components)
+	}
+}
+
+func TestScraper_Scrape_hooks(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.OnComponent(func(c *model.Component, ctx scraper.HookContext) bool {
+		if ctx.Name == "test.RootHasInfoWithComponentHasInfoValue" {
+			return false
+		}
+		c.ID = "ignored"
+		c.Tags = append(c.Tags, "parent:"+ctx.Parent.Name)
+		return true
+	}))
+	require.NoError(t, s.OnRelation(func(r *model.Relation, ctx scraper.HookContext) bool {
+		r.Label = "uses " + ctx.Field
+		return true
+	}))
+
+	var visits []string
+	require.NoError(t, s.OnVisit(func(v reflect.Value) {
+		visits = append(visits, v.Type().String())
+	}))
+
+	structure := s.Scrape(test.NewRootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels())
+
+	rootID := componentID("RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels")
+	valueID := componentID("PublicComponentHasInfo")
+	require.Len(t, structure.Components, 2)
+	require.Equal(t, []string{"parent:"}, structure.Components[rootID].Tags)
+	require.Equal(t, []string{"parent:test.RootWithPublicPublicComponentHasInfoValueAtMultipleLevels"}, structure.Components[valueID].Tags)
+	require.Equal(t, "uses Value", structure.Relations[rootID][valueID].Label)
+
+	require.Equal(t, "test.RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels", visits[0])
+	require.Contains(t, visits, "test.RootHasInfoWithComponentHasInfoValue")
+}
+
+func TestScraper_TryScrape_hooks_veto_relations(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.OnRelation(func(r *model.Relation, ctx scraper.HookContext) bool {
+		return false
+	}))
+	require.NoError(t, s.OnVisit(func(v reflect.Value) {
+		panic("visit failed")
+	}))
+	require.Error(t, s.OnComponent(nil))
+
+	structure, err := s.TryScrape(test.NewRootHasInfoWithComponentHasInfoValue())
+	require.Len(t, structure.Components, 2)
+	require.Empty(t, structure.Relations)
+
+	var errs scraper.Errors
+	require.ErrorAs(t, err, &errs)
+	require.Equal(t, scraper.PanicError{
+		Type:   "test.RootHasInfoWithComponentHasInfoValue",
+		Source: "visit hook #0",
+		Value:  "visit failed",
+	}, errs[0])
+}
+
+func TestScraper_Scrape_component_hooks_called_once(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.PublicComponentHasInfo$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name, "ruled")
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	calls := make(map[string]int)
+	require.NoError(t, s.OnComponent(func(c *model.Component, ctx scraper.HookContext) bool {
+		calls[ctx.Name]++
+		c.Tags = append(c.Tags, "hooked")
+		return true
+	}))
+
+	structure := s.Scrape(test.NewRootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels())
+
+	valueID := componentID("PublicComponentHasInfo")
+	require.Equal(t, map[string]int{
+		"test.RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels": 1,
+		"test.RootHasInfoWithComponentHasInfoValue":                             1,
+		"test.PublicComponentHasInfo":                                           1,
+	}, calls)
+	require.Equal(t, "ruled", structure.Components[valueID].Description)
+	require.Equal(t, []string{"hooked"}, structure.Components[valueID].Tags)
+	require.Contains(t, structure.Relations[componentID("RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels")], valueID)
+	require.Contains(t, structure.Relations[componentID("RootHasInfoWithComponentHasInfoValue")], valueID)
+}
+
+type nameOnlyRule struct{}
+
+func (r nameOnlyRule) Applies(pkg string, name string) bool {
+	return name == "test.PublicClosingComponent"
+}
+
+func (r nameOnlyRule) Apply(name string) model.Info {
+	return model.ComponentInfo(name, "adapted")
+}
+
+func TestScraper_Scrape_logger(t *testing.T) {
+	var buf bytes.Buffer
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s := scraper.NewScraper(c)
+	_ = s.Scrape(test.NewRootHasInfoWithMatchedFields())
+
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var r map[string]interface{}
+		require.NoError(t, decoder.Decode(&r))
+		records = append(records, r)
+	}
+	require.NotEmpty(t, records)
+
+	var store map[string]interface{}
+	for _, r := range records {
+		if r["name"] == "test.PublicComponent" && r["strategy"] == "struct" {
+			store = r
+			break
+		}
+	}
+	require.NotNil(t, store)
+	require.Equal(t, "DEBUG", store["level"])
+	require.Equal(t, float64(1), store["depth"])
+	require.Equal(t, componentID("PublicComponent"), store["id"])
+	require.Equal(t, "test.PublicComponent", store["type"])
+	require.Equal(t, componentID("RootHasInfoWithMatchedFields"), store["parent"])
+}
+
+func TestScraper_Scrape_logger_invalid_value(t *testing.T) {
+	var buf bytes.Buffer
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s := scraper.NewScraper(c)
+	require.NotPanics(t, func() {
+		structure := s.Scrape(nil)
+		require.Empty(t, structure.Components)
+	})
+	require.NotEmpty(t, buf.String())
+}
+
+func TestScraper_Scrape_logger_level(t *testing.T) {
+	var buf bytes.Buffer
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	s := scraper.NewScraper(c)
+	_ = s.Scrape(test.NewRootHasInfoWithMatchedFields())
+
+	require.Empty(t, buf.String())
+}
+
+func TestAdaptRule(t *testing.T) {
+	r := scraper.AdaptRule(nameOnlyRule{})
+
+	ctx := scraper.MatchContext{
+		Name: "test.PublicClosingComponent",
+		Kind: reflect.Struct,
+	}
+	require.True(t, r.Matches(ctx))
+	require.Equal(t, model.ComponentInfo(ctx.Name, "adapted"), r.ApplyContext(ctx))
+
+	ctx.Kind = reflect.Func
+	require.False(t, r.Matches(ctx))
+
+	built, err := scraper.NewRule().
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+	require.IsType(t, built, scraper.AdaptRule(built))
+	require.Implements(t, (*scraper.MatchingRule)(nil), built)
+}
+
+func TestRuleBuilder_Build_apply_funcs(t *testing.T) {
+	_, err := scraper.NewRule().Build()
+	require.Error(t, err)
+
+	_, err = scraper.NewRule().
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		WithContextApplyFunc(func(ctx scraper.MatchContext, groups ...string) model.Info {
+			return model.ComponentInfo(ctx.Name)
+		}).
+		Build()
+	require.Error(t, err)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.(\w*)Client$`).
+		WithContextApplyFunc(func(ctx scraper.MatchContext, groups ...string) model.Info {
+			return model.ComponentInfo(groups[0], ctx.Name)
+		}).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, model.ComponentInfo("Kafka", "test.KafkaClient"), r.Apply("test.KafkaClient"))
+}
+
+func TestScraper_Scrape_ignore_rules(t *testing.T) {
+	var tests = []struct {
+		name               string
+		mode               scraper.IgnoreMode
+		expectedComponents map[string]string
+		expectedRelations  map[string][]string
+	}{
+		{
+			name: "ignore all",
+			mode: scraper.IgnoreAll,
+			expectedComponents: map[string]string{
+				componentID("RootHasInfoWithNestedComponents"): "test.RootHasInfoWithNestedComponents",
+			},
+			expectedRelations: map[string][]string{},
+		},
+		{
+			name: "ignore component",
+			mode: scraper.IgnoreComponent,
+			expectedComponents: map[string]string{
+				componentID("RootHasInfoWithNestedComponents"): "test.RootHasInfoWithNestedComponents",
+				componentID("PublicComponentHasInfo"):          "test.PublicComponentHasInfo",
+			},
+			expectedRelations: map[string][]string{
+				componentID("RootHasInfoWithNestedComponents"): {
+					componentID("PublicComponentHasInfo"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+
+			r, err := scraper.NewIgnoreRule().
+				WithPkgRegexps(testPKG).
+				WithNameRegexp(`^test\.RootHasInfoWithComponentHasInfoPointer$`).
+				WithMode(tt.mode).
+				Build()
+			require.NoError(t, err)
+
+			s := scraper.NewScraper(c)
+			err = s.RegisterIgnoreRule(r)
+			require.NoError(t, err)
+
+			result := s.Scrape(test.NewRootHasInfoWithNestedComponents())
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+			requireEqualRelations(t, tt.expectedRelations, result.Relations)
+		})
+	}
+}
+
+func TestScraper_Scrape_ignore_rules_interfaces(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.privateInterface$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	result := s.Scrape(test.NewRootWithPublicPrivateInterfaceWithNil())
+	require.Len(t, result.Components, 1)
+
+	ir, err := scraper.NewIgnoreRule().
+		WithNameRegexp(`Interface$`).
+		WithMode(scraper.IgnoreComponent).
+		Build()
+	require.NoError(t, err)
+
+	s = scraper.NewScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+	require.NoError(t, s.RegisterIgnoreRule(ir))
+
+	result = s.Scrape(test.NewRootWithPublicPrivateInterfaceWithNil())
+	require.Empty(t, result.Components)
+}
+
+func TestScraper_Scrape_excluded_packages(t *testing.T) {
+	var tests = []struct {
+		name   string
+		config func(c *scraper.Configuration)
+	}{
+		{
+			name: "excluded package prefix",
+			config: func(c *scraper.Configuration) {
+				c.ExcludedPackages = []string{testPKG}
+			},
+		},
+		{
+			name: "excluded package regexp",
+			config: func(c *scraper.Configuration) {
+				c.ExcludedPackageRegexps = []*regexp.Regexp{regexp.MustCompile(`/internal/test$`)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				"github.com/krzysztofreczek/go-structurizr/pkg/internal",
+			)
+
+			result := scraper.NewScraper(c).Scrape(test.NewRootHasInfoWithNestedComponents())
+			require.Len(t, result.Components, 3)
+
+			tt.config(&c)
+
+			result = scraper.NewScraper(c).Scrape(test.NewRootHasInfoWithNestedComponents())
+			require.Empty(t, result.Components)
+		})
+	}
+}
+
+func TestScraper_Scrape_id_strategy(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.IDStrategy = scraper.ReadableIDStrategy
+
+	s := scraper.NewScraper(c)
+	result := s.Scrape(test.NewRootHasInfoWithNestedComponents())
+
+	readableID := func(name string) string {
+		return "github_com_krzysztofreczek_go_structurizr_pkg_internal_test_" + name
+	}
+
+	expectedComponentIDs := map[string]struct{}{
+		readableID("RootHasInfoWithNestedComponents"):        {},
+		readableID("RootHasInfoWithComponentHasInfoPointer"): {},
+		readableID("PublicComponentHasInfo"):                 {},
+	}
+	expectedRelations := map[string][]string{
+		readableID("RootHasInfoWithNestedComponents"): {
+			readableID("RootHasInfoWithComponentHasInfoPointer"),
+		},
+		readableID("RootHasInfoWithComponentHasInfoPointer"): {
+			readableID("PublicComponentHasInfo"),
+		},
+	}
+	requireEqualComponentIDs(t, expectedComponentIDs, result.Components)
+	requireEqualRelations(t, expectedRelations, result.Relations)
+}
+
+func TestScraper_TryScrape_id_collision(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.IDStrategy = func(pkg string, typeName string) string {
+		return "ID"
+	}
+
+	s := scraper.NewScraper(c)
+	result, err := s.TryScrape(test.NewRootHasInfoWithNestedComponents())
+	require.Error(t, err)
+
+	var collision scraper.IDCollisionError
+	require.ErrorAs(t, err, &collision)
+	require.Equal(t, "ID", collision.ID)
+	require.Equal(t, testPKG+".RootHasInfoWithNestedComponents", collision.Type)
+	require.Equal(t, testPKG+".RootHasInfoWithComponentHasInfoPointer", collision.CollidingType)
+
+	require.Len(t, result.Components, 1)
+	require.Equal(t, "test.RootHasInfoWithNestedComponents", result.Components["ID"].Name)
+}
+
+func TestScraper_Scrape_logs_id_collision(t *testing.T) {
+	var buf bytes.Buffer
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.IDStrategy = func(pkg string, typeName string) string {
+		return "ID"
+	}
+	c.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	result := scraper.NewScraper(c).Scrape(test.NewRootHasInfoWithNestedComponents())
+	require.Len(t, result.Components, 1)
+
+	var record map[string]interface{}
+	require.NoError(t, json.NewDecoder(&buf).Decode(&record))
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, "scraping error", record["msg"])
+	require.Equal(t, scraper.IDCollisionError{
+		ID:            "ID",
+		Type:          testPKG + ".RootHasInfoWithNestedComponents",
+		CollidingType: testPKG + ".RootHasInfoWithComponentHasInfoPointer",
+	}.Error(), record["error"])
+}
+
+func TestScraper_TryScrape_without_errors(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewScraper(c)
+	result, err := s.TryScrape(test.NewRootHasInfoWithNestedComponents())
+	require.NoError(t, err)
+	require.Len(t, result.Components, 3)
+}
+
 func requireEqualComponentIDs(
 	t *testing.T,
 	expectedComponentIDs map[string]struct{},
@@ -1112,10 +2459,23 @@ func requireEqualComponents(
 	}
 }
 
+func requireEqualComponentNames(
+	t *testing.T,
+	expectedComponentNames map[string]string,
+	actualComponents map[string]model.Component,
+) {
+	require.Len(t, actualComponents, len(expectedComponentNames))
+	for id, expectedName := range expectedComponentNames {
+		actualComponent, contains := actualComponents[id]
+		require.True(t, contains, "actual components: %+v; expected components: %+v", actualComponents, expectedComponentNames)
+		require.Equal(t, expectedName, actualComponent.Name)
+	}
+}
+
 func requireEqualRelations(
 	t *testing.T,
 	expectedRelations map[string][]string,
-	actualRelations map[string]map[string]struct{},
+	actualRelations map[string]map[string]model.Relation,
 ) {
 	require.Len(t, actualRelations, len(expectedRelations))
 	for id, expectedRelationIDs := range expectedRelations {
@@ -1134,7 +2494,30 @@ func componentID(name string) string {
 	return internal.Hash(id)
 }
 
+func externalComponentID(name string) string {
+	return internal.Hash(fmt.Sprintf(".%s", name))
+}
+
 func componentIDf(name string, args ...any) string {
 	name = fmt.Sprintf(name, args...)
 	return componentID(name)
 }
+
+func TestNewScraperFromConfigFile_implements(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(fileName, []byte(`
+rules:
+  - name_regexp: ".*"
+    match:
+      implements: "io.Closer"
+    component:
+      name: "Closer"
+`), 0o600)
+	require.NoError(t, err)
+
+	_, err = scraper.NewScraperFromConfigFile(fileName)
+	require.ErrorContains(t, err, "`implements` condition is supported by the static scraper only")
+
+	_, err = scraper.NewStaticScraperFromConfigFile(fileName)
+	require.NoError(t, err)
+}
@@ -0,0 +1,906 @@
+package scraper
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/yaml"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	modelPkg = "github.com/krzysztofreczek/go-structurizr/pkg/model"
+
+	staticLoadMode = packages.NeedName |
+		packages.NeedTypes |
+		packages.NeedTypesInfo |
+		packages.NeedSyntax |
+		packages.NeedImports |
+		packages.NeedDeps
+)
+
+// StaticScraper represents the responsibilities of a scraper working on
+// the source code instead of the application instance.
+//
+// ScrapeType loads the given package with its dependencies, finds the named
+// root type and walks its struct fields, embedded types and method signatures
+// according to the internal configuration and registered rules.
+// It returns an open `model.Structure` containing recognized components and
+// the relationships between them, or an error if the package cannot be loaded
+// or the root type cannot be found.
+//
+// ScrapeTypeWithReport works the same way as ScrapeType, but additionally
+// returns the `Report` describing the scraping process, including
+// the loading of the packages.
+//
+// RegisterRule registers a `Rule` with the scraper. It will return an error
+// if the provided rule is nil.
+//
+// RegisterContextRule registers a `ContextRule` with the scraper. It will return
+// an error if the provided rule is nil.
+//
+// RegisterIgnoreRule registers an `IgnoreRule` with the scraper. It will return
+// an error if the provided rule is nil.
+//
+// OnComponent and OnRelation register hooks called for each component and
+// relation respectively. They will return an error if the provided hook is nil.
+//
+// Each call scrapes into a new structure, independent of the previous calls.
+// The scraper is safe for concurrent use by multiple goroutines.
+type StaticScraper interface {
+	ScrapeType(pkg string, name string) (model.Structure, error)
+	ScrapeTypeWithReport(pkg string, name string) (model.Structure, Report, error)
+	RegisterRule(r Rule) error
+	RegisterContextRule(r ContextRule) error
+	RegisterIgnoreRule(r IgnoreRule) error
+	OnComponent(h ComponentHook) error
+	OnRelation(h RelationHook) error
+}
+
+type staticScraper struct {
+	*scraper
+	packages map[string]*packages.Package
+	decls    map[*types.Func]*ast.FuncDecl
+	visited  map[string]struct{}
+	scraping map[string]struct{}
+}
+
+// NewStaticScraper creates a new StaticScraper instance using the provided
+// Configuration.
+//
+// Packages are loaded relative to the current working directory, hence
+// the scraped package must be resolvable from within the current module.
+func NewStaticScraper(config Configuration) StaticScraper {
+	return newStaticScraper(newScraper(config, make([]ContextRule, 0), make([]IgnoreRule, 0)))
+}
+
+// NewStaticScraperFromConfigFile creates a new StaticScraper instance using
+// Configuration loaded from the specified YAML configuration file.
+//
+// It returns an error if the YAML file does not exist or contains invalid content.
+func NewStaticScraperFromConfigFile(fileName string) (StaticScraper, error) {
+	configuration, err := yaml.LoadFromFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load configuration from file `%s`", fileName)
+	}
+
+	config, err := toScraperConfig(configuration)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load scraper configuration from file `%s`", fileName)
+	}
+
+	rules, err := toScraperRules(configuration)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load scraper rules from file `%s`", fileName)
+	}
+
+	ignoreRules, err := toScraperIgnoreRules(configuration)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"could not load scraper ignore rules from file `%s`", fileName)
+	}
+
+	return newStaticScraper(newScraper(config, adaptRules(rules), ignoreRules)), nil
+}
+
+func newStaticScraper(s *scraper) *staticScraper {
+	return &staticScraper{
+		scraper: s,
+	}
+}
+
+// newRun returns a static scraper with the configuration and the rules
+// registered so far, and a fresh scraping state.
+func (s *staticScraper) newRun() *staticScraper {
+	return &staticScraper{
+		scraper:  s.scraper.newRun(),
+		decls:    make(map[*types.Func]*ast.FuncDecl),
+		visited:  make(map[string]struct{}),
+		scraping: make(map[string]struct{}),
+	}
+}
+
+// ScrapeType loads the given package and scrapes the type of the given name
+// according to the internal configuration and registered rules.
+//
+// Since the application is never instantiated, the dynamic values of
+// interfaces are unknown. Interface types are matched against the rules
+// only, the same way the default scraper treats nil interfaces.
+// Information of types implementing `model.HasInfo` is resolved
+// from the `Info()` method body, as long as it returns either
+// a `model.ComponentInfo` call or a `model.Info` literal built of constants.
+// Otherwise, the component is named after its type.
+func (s *staticScraper) ScrapeType(pkg string, name string) (model.Structure, error) {
+	return s.newRun().scrapeTypeByName(pkg, name)
+}
+
+// ScrapeTypeWithReport works the same way as ScrapeType, but additionally
+// returns the `Report` describing the scraping process.
+func (s *staticScraper) ScrapeTypeWithReport(pkg string, name string) (model.Structure, Report, error) {
+	run := s.newRun()
+
+	var structure model.Structure
+	var err error
+	report := run.withReport(func() {
+		structure, err = run.scrapeTypeByName(pkg, name)
+	})
+	return structure, report, err
+}
+
+func (s *staticScraper) scrapeTypeByName(pkg string, name string) (model.Structure, error) {
+	root, err := s.load(pkg)
+	if err != nil {
+		return model.Structure{}, err
+	}
+
+	obj, ok := root.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return model.Structure{}, errors.Errorf(
+			"could not find type `%s` in package `%s`", name, pkg)
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if ok && named.TypeParams().Len() > 0 {
+		return model.Structure{}, errors.Errorf(
+			"generic type `%s` in package `%s` cannot be scraped without type arguments", name, pkg)
+	}
+
+	s.scrapeRootType(obj.Type())
+
+	return s.structure, s.err()
+}
+
+func (s *staticScraper) scrapeRootType(t types.Type) {
+	defer s.recoverPanic(t.String(), "scraping", "")
+
+	s.scrapeType(t, origin{}, 0)
+}
+
+func (s *staticScraper) load(pkg string) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode:      staticLoadMode,
+		ParseFile: parseFile,
+	}
+
+	pkgs, err := packages.Load(cfg, pkg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load package `%s`", pkg)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, errors.Errorf(
+			"expected exactly one package matching `%s`, got %d", pkg, len(pkgs))
+	}
+
+	root := pkgs[0]
+	for _, e := range root.Errors {
+		// type errors are expected, as function bodies are dropped
+		// while parsing; declarations remain fully type-checked
+		if e.Kind != packages.TypeError {
+			return nil, errors.Errorf(
+				"could not load package `%s`: %s", pkg, e)
+		}
+	}
+
+	s.packages = make(map[string]*packages.Package)
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		s.packages[p.PkgPath] = p
+	})
+
+	return root, nil
+}
+
+// parseFile parses the source file dropping the bodies of all functions
+// except `Info()`, `Relations()` and `InstanceID()` methods, as those
+// are the only ones the scraper evaluates.
+// It significantly reduces the cost of type-checking the dependencies.
+func parseFile(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	f, err := parser.ParseFile(fset, filename, src, parser.AllErrors)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range f.Decls {
+		decl, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if decl.Recv == nil || !isEvaluatedMethod(decl.Name.Name) {
+			decl.Body = nil
+		}
+	}
+
+	return f, nil
+}
+
+func isEvaluatedMethod(name string) bool {
+	return name == "Info" || name == "Relations" || name == "InstanceID"
+}
+
+func (s *staticScraper) scrapeType(
+	t types.Type,
+	o origin,
+	level int,
+) {
+	if s.isAborted() || s.isTooDeep(o, level) {
+		return
+	}
+
+	defer s.enter(typeStrategyName(t), o, level)()
+
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		s.scrapeNamed(t, o, level)
+	case *types.Pointer:
+		s.scrapeType(t.Elem(), o, level)
+	case *types.Slice:
+		s.scrapeType(t.Elem(), o.element(model.RelationKindSliceElement), level)
+	case *types.Array:
+		s.scrapeType(t.Elem(), o.element(model.RelationKindSliceElement), level)
+	case *types.Map:
+		s.scrapeType(t.Elem(), o.element(model.RelationKindMapValue), level)
+	case *types.Signature:
+		s.scrapeSignature(t, o, level)
+	case *types.Struct:
+		// anonymous structs are named after the closest named type
+		// they are declared in and the path leading to them
+		s.scrapeStruct(t, t, o.ownerPkg, o.ownerPath, o, level)
+	}
+}
+
+func (s *staticScraper) scrapeNamed(
+	t *types.Named,
+	o origin,
+	level int,
+) {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		s.scrapeStruct(t, u, typePackage(t), s.typeName(typeName(t)), o, level)
+	case *types.Interface:
+		s.scrapeInterface(t, o, level)
+	default:
+		s.scrapeOtherNamed(t, o, level)
+	}
+}
+
+func (s *staticScraper) scrapeInterface(
+	t *types.Named,
+	o origin,
+	level int,
+) {
+	pkg, name := typePackage(t), s.typeName(typeName(t))
+	id := s.componentID(pkg, name)
+
+	if s.isPackageExcluded(pkg) {
+		s.debugType(componentName(pkg, name), id, "type package '%s' is excluded from scraping", pkg)
+		s.reportSkippedPackage(pkg)
+		return
+	}
+	s.reportVisit(pkg, name)
+
+	if _, ignored := s.ignoreMode(pkg, componentName(pkg, name)); ignored {
+		s.debugType(componentName(pkg, name), id, "type is ignored by one of the ignore rules")
+		return
+	}
+
+	info, ok := s.applyRules(s.staticMatchContext(t, pkg, componentName(pkg, name), o, level))
+	if ok {
+		s.debugType(componentName(pkg, name), id, "resolved info data %+v from one of the rules", info)
+	}
+	switch {
+	case ok:
+		_ = s.addComponent(pkg, name, info, o)
+	case o.annotation != nil:
+		_ = s.addAnnotatedComponent(pkg, name, o)
+	default:
+		s.reportUnmatched(pkg, name)
+	}
+}
+
+// scrapeOtherNamed scrapes named types other than structs and interfaces,
+// e.g. functions or maps, which become components only when matched
+// by rules with matchers.
+func (s *staticScraper) scrapeOtherNamed(
+	t *types.Named,
+	o origin,
+	level int,
+) {
+	pkg, name := typePackage(t), s.typeName(typeName(t))
+	id := s.componentID(pkg, name)
+	cName := componentName(pkg, name)
+
+	// named types may refer to themselves, e.g. `type StateFn func() StateFn`
+	leave, ok := s.enterType(t, id, o)
+	if !ok {
+		s.debugType(cName, id, "type has already been scraped in this context, skipping")
+		return
+	}
+	defer leave()
+
+	if !s.isPackageScrappable(pkg) {
+		s.reportSkippedPackage(pkg)
+		if c := s.addAnnotatedComponent(pkg, name, o); c.ID != "" {
+			o = newOrigin(c.ID, shortTypeName(name))
+		}
+		s.scrapeType(t.Underlying(), o, level)
+		return
+	}
+
+	s.reportVisit(pkg, name)
+
+	mode, ignored := s.ignoreMode(pkg, cName)
+	if ignored {
+		s.debugType(cName, id, "type is ignored by one of the ignore rules")
+		if mode == IgnoreAll {
+			return
+		}
+	}
+
+	if !ignored {
+		o = o.withInstance(s.getInstanceIDFromMethod(t, pkg, name))
+
+		info, ok := s.applyRules(s.staticMatchContext(t, pkg, cName, o, level))
+		if ok {
+			s.debugType(cName, id, "resolved info data %+v from one of the rules", info)
+		}
+		var c model.Component
+		if ok {
+			c = s.addComponent(pkg, name, info, o)
+		} else {
+			c = s.addAnnotatedComponent(pkg, name, o)
+		}
+		if c.ID != "" {
+			o = newOrigin(c.ID, shortTypeName(name))
+		}
+	}
+
+	s.scrapeType(t.Underlying(), o, level)
+}
+
+// enterType marks the type as scraped within the closest parent component,
+// and returns the function to be called once the type is scraped.
+// It returns false if the type has already been scraped in this context,
+// or it is still being scraped, i.e. the type refers to itself.
+func (s *staticScraper) enterType(t types.Type, id string, o origin) (func(), bool) {
+	usageKey := s.usageKey(t, id, o)
+	recursionKey := fmt.Sprintf("%s-%s-%s", o.parentID, id, types.TypeString(t, nil))
+	if _, ok := s.visited[usageKey]; ok {
+		return nil, false
+	}
+	if _, ok := s.scraping[recursionKey]; ok {
+		return nil, false
+	}
+
+	s.visited[usageKey] = struct{}{}
+	s.scraping[recursionKey] = struct{}{}
+	return func() {
+		delete(s.scraping, recursionKey)
+	}, true
+}
+
+// usageKey identifies the usage of the type within the closest parent
+// component. Merged generic types are distinguished by their type arguments,
+// as fields of each instantiation may differ. Values of annotated fields,
+// and all the values in the InstancesSeparate mode, are distinguished
+// by their paths, as they become components of their own.
+func (s *staticScraper) usageKey(t types.Type, id string, o origin) string {
+	key := fmt.Sprintf("%s-%s-%s", o.parentID, id, types.TypeString(t, nil))
+	if o.annotation != nil || s.config.Instances == InstancesSeparate {
+		key += "@" + o.path
+	}
+	return key
+}
+
+func (s *staticScraper) scrapeStruct(
+	t types.Type,
+	st *types.Struct,
+	pkg string,
+	name string,
+	o origin,
+	level int,
+) {
+	id := s.componentID(pkg, name)
+	cName := componentName(pkg, name)
+
+	if !s.isPackageScrappable(pkg) {
+		s.debugType(cName, id, "type package '%s' IS NOT applicable for scraping", pkg)
+		s.reportSkippedPackage(pkg)
+		_ = s.addAnnotatedComponent(pkg, name, o)
+		return
+	}
+	s.reportVisit(pkg, name)
+
+	leave, ok := s.enterType(t, id, o)
+	if !ok {
+		s.debugType(cName, id, "type has already been scraped in this context, skipping")
+		return
+	}
+	defer leave()
+
+	mode, ignored := s.ignoreMode(pkg, cName)
+	if ignored && mode == IgnoreAll {
+		s.debugType(cName, id, "type is ignored by one of the ignore rules, skipping")
+		return
+	}
+
+	var c model.Component
+	o = o.withInstance(s.getInstanceIDFromMethod(t, pkg, name))
+
+	if !ignored {
+		info, ok := s.getInfoFromMethod(t, pkg, name)
+		if ok {
+			s.debugType(cName, id, "resolved info data %+v from .Info() method", info)
+		}
+		if ruleInfo, matched := s.applyRules(s.staticMatchContext(t, pkg, cName, o, level)); matched {
+			s.debugType(cName, id, "resolved info data %+v from one of the rules", ruleInfo)
+			info, ok = ruleInfo, true
+		}
+		if ok {
+			c = s.addComponent(pkg, name, info, o)
+		}
+
+		if c.ID == "" {
+			c = s.addAnnotatedComponent(pkg, name, o)
+		}
+		if c.ID == "" {
+			s.reportUnmatched(pkg, name)
+		}
+	}
+
+	if c.ID != "" {
+		s.addExplicitRelations(c, s.getRelationsFromMethod(t, pkg, name))
+		o = newOrigin(c.ID, shortTypeName(name))
+	}
+	if _, ok := t.(*types.Named); ok {
+		o = o.ownedBy(pkg, shortTypeName(name))
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		fo := o.viaField(f.Name(), reflect.StructTag(st.Tag(i)))
+
+		a, skip := s.fieldAnnotation(fo)
+		if skip {
+			s.debugType(cName, id, "field `%s` is skipped by the `%s:\"-\"` struct tag", f.Name(), AnnotationTagKey)
+			continue
+		}
+		fo.annotation = a
+
+		s.scrapeType(f.Type(), fo, level+1)
+	}
+
+	methods := types.NewMethodSet(types.NewPointer(t))
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.At(i).Obj()
+		if !m.Exported() {
+			continue
+		}
+		s.scrapeType(m.Type(), o.via("."+m.Name(), o.kind), level+1)
+	}
+}
+
+func (s *staticScraper) scrapeSignature(
+	t *types.Signature,
+	o origin,
+	level int,
+) {
+	for i := 0; i < t.Params().Len(); i++ {
+		s.scrapeType(t.Params().At(i).Type(), o.via("()", model.RelationKindMethodArg), level)
+	}
+
+	for i := 0; i < t.Results().Len(); i++ {
+		s.scrapeType(t.Results().At(i).Type(), o.via("()", model.RelationKindMethodReturn), level)
+	}
+}
+
+func (s *staticScraper) getInfoFromMethod(t types.Type, pkg string, name string) (model.Info, bool) {
+	m, ok := lookupMethod(t, "Info")
+	if !ok || !isInfoMethod(m) {
+		return model.Info{}, false
+	}
+
+	var info model.Info
+	ok = s.evalMethod(m, func(p *packages.Package, expr ast.Expr) bool {
+		info, ok = evalInfo(p.TypesInfo, expr)
+		return ok
+	})
+	if !ok {
+		s.debugType(componentName(pkg, name), s.componentID(pkg, name),
+			"could not resolve .Info() method statically, falling back to the type name")
+		return model.ComponentInfo(componentName(pkg, name)), true
+	}
+
+	return info, true
+}
+
+// getInstanceIDFromMethod returns the key identifying the instance
+// in the InstancesSeparate mode, as long as the `InstanceID()` method
+// returns a constant.
+func (s *staticScraper) getInstanceIDFromMethod(t types.Type, pkg string, name string) string {
+	if s.config.Instances != InstancesSeparate {
+		return ""
+	}
+
+	m, ok := lookupMethod(t, "InstanceID")
+	if !ok || !isInstanceIDMethod(m) {
+		return ""
+	}
+
+	var id string
+	ok = s.evalMethod(m, func(p *packages.Package, expr ast.Expr) bool {
+		id, ok = evalString(p.TypesInfo, expr)
+		return ok
+	})
+	if !ok {
+		s.debugType(componentName(pkg, name), s.componentID(pkg, name),
+			"could not resolve .InstanceID() method statically, falling back to the field path")
+		return ""
+	}
+
+	return id
+}
+
+func (s *staticScraper) getRelationsFromMethod(t types.Type, pkg string, name string) []model.RelationInfo {
+	m, ok := lookupMethod(t, "Relations")
+	if !ok || !isRelationsMethod(m) {
+		return nil
+	}
+
+	var relations []model.RelationInfo
+	ok = s.evalMethod(m, func(p *packages.Package, expr ast.Expr) bool {
+		relations, ok = evalRelations(p.TypesInfo, expr)
+		return ok
+	})
+	if !ok {
+		s.debugType(componentName(pkg, name), s.componentID(pkg, name),
+			"could not resolve .Relations() method statically, skipping")
+		return nil
+	}
+
+	return relations
+}
+
+// evalMethod evaluates the only return statement of the method body
+// with the given function.
+func (s *staticScraper) evalMethod(
+	m *types.Func,
+	eval func(p *packages.Package, expr ast.Expr) bool,
+) bool {
+	m = m.Origin()
+
+	p, ok := s.packages[m.Pkg().Path()]
+	if !ok {
+		return false
+	}
+
+	decl := s.funcDecl(p, m)
+	if decl == nil || decl.Body == nil || len(decl.Body.List) != 1 {
+		return false
+	}
+
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+
+	return eval(p, ret.Results[0])
+}
+
+func (s *staticScraper) funcDecl(p *packages.Package, m *types.Func) *ast.FuncDecl {
+	if decl, ok := s.decls[m]; ok {
+		return decl
+	}
+
+	for _, f := range p.Syntax {
+		for _, d := range f.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if p.TypesInfo.Defs[decl.Name] == m {
+				s.decls[m] = decl
+				return decl
+			}
+		}
+	}
+
+	return nil
+}
+
+func evalInfo(info *types.Info, expr ast.Expr) (model.Info, bool) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return evalInfoCall(info, e)
+	case *ast.CompositeLit:
+		return evalInfoLiteral(info, e)
+	}
+	return model.Info{}, false
+}
+
+func evalInfoCall(info *types.Info, call *ast.CallExpr) (model.Info, bool) {
+	if !isModelFunc(info, call.Fun, "ComponentInfo") || call.Ellipsis.IsValid() {
+		return model.Info{}, false
+	}
+
+	args := make([]string, len(call.Args))
+	for i, arg := range call.Args {
+		v, ok := evalString(info, arg)
+		if !ok {
+			return model.Info{}, false
+		}
+		args[i] = v
+	}
+
+	return model.ComponentInfo(args...), true
+}
+
+func evalInfoLiteral(info *types.Info, lit *ast.CompositeLit) (model.Info, bool) {
+	if !isModelType(info.TypeOf(lit), "Info") {
+		return model.Info{}, false
+	}
+
+	i := model.Info{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return model.Info{}, false
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return model.Info{}, false
+		}
+
+		if key.Name == "Tags" {
+			tags, ok := evalStrings(info, kv.Value)
+			if !ok {
+				return model.Info{}, false
+			}
+			i.Tags = tags
+			continue
+		}
+
+		v, ok := evalString(info, kv.Value)
+		if !ok {
+			return model.Info{}, false
+		}
+
+		switch key.Name {
+		case "Name":
+			i.Name = v
+		case "Kind":
+			i.Kind = v
+		case "Description":
+			i.Description = v
+		case "Technology":
+			i.Technology = v
+		}
+	}
+
+	return i, true
+}
+
+func evalRelations(info *types.Info, expr ast.Expr) ([]model.RelationInfo, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	relations := make([]model.RelationInfo, len(lit.Elts))
+	for i, elt := range lit.Elts {
+		r, ok := evalRelation(info, elt)
+		if !ok {
+			return nil, false
+		}
+		relations[i] = r
+	}
+
+	return relations, true
+}
+
+func evalRelation(info *types.Info, expr ast.Expr) (model.RelationInfo, bool) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return evalRelationCall(info, e)
+	case *ast.CompositeLit:
+		return evalRelationLiteral(info, e)
+	}
+	return model.RelationInfo{}, false
+}
+
+func evalRelationCall(info *types.Info, call *ast.CallExpr) (model.RelationInfo, bool) {
+	if !isModelFunc(info, call.Fun, "RelationTo") || call.Ellipsis.IsValid() || len(call.Args) == 0 {
+		return model.RelationInfo{}, false
+	}
+
+	target, ok := evalInfo(info, call.Args[0])
+	if !ok {
+		return model.RelationInfo{}, false
+	}
+
+	args := make([]string, len(call.Args)-1)
+	for i, arg := range call.Args[1:] {
+		v, ok := evalString(info, arg)
+		if !ok {
+			return model.RelationInfo{}, false
+		}
+		args[i] = v
+	}
+
+	return model.RelationTo(target, args...), true
+}
+
+func evalRelationLiteral(info *types.Info, lit *ast.CompositeLit) (model.RelationInfo, bool) {
+	if !isModelType(info.TypeOf(lit), "RelationInfo") {
+		return model.RelationInfo{}, false
+	}
+
+	r := model.RelationInfo{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return model.RelationInfo{}, false
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return model.RelationInfo{}, false
+		}
+
+		switch key.Name {
+		case "Target":
+			r.Target, ok = evalInfo(info, kv.Value)
+		case "Tags":
+			r.Tags, ok = evalStrings(info, kv.Value)
+		case "Description":
+			r.Description, ok = evalString(info, kv.Value)
+		case "Technology":
+			r.Technology, ok = evalString(info, kv.Value)
+		}
+		if !ok {
+			return model.RelationInfo{}, false
+		}
+	}
+
+	return r, true
+}
+
+func evalString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func evalStrings(info *types.Info, expr ast.Expr) ([]string, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	values := make([]string, len(lit.Elts))
+	for i, elt := range lit.Elts {
+		v, ok := evalString(info, elt)
+		if !ok {
+			return nil, false
+		}
+		values[i] = v
+	}
+
+	return values, true
+}
+
+func lookupMethod(t types.Type, name string) (*types.Func, bool) {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, name)
+	m, ok := obj.(*types.Func)
+	return m, ok
+}
+
+func isRelationsMethod(m *types.Func) bool {
+	sig, ok := m.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	slice, ok := sig.Results().At(0).Type().(*types.Slice)
+	return ok && isModelType(slice.Elem(), "RelationInfo")
+}
+
+func isInstanceIDMethod(m *types.Func) bool {
+	sig, ok := m.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	basic, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && basic.Kind() == types.String
+}
+
+func isInfoMethod(m *types.Func) bool {
+	sig, ok := m.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	return isModelType(sig.Results().At(0).Type(), "Info")
+}
+
+func isModelType(t types.Type, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == modelPkg && obj.Name() == name
+}
+
+func isModelFunc(info *types.Info, expr ast.Expr, name string) bool {
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return false
+	}
+
+	f, ok := info.Uses[ident].(*types.Func)
+	return ok && f.Pkg() != nil && f.Pkg().Path() == modelPkg && f.Name() == name
+}
+
+func typePackage(t *types.Named) string {
+	if t.Obj().Pkg() == nil {
+		return ""
+	}
+	return t.Obj().Pkg().Path()
+}
+
+// typeName returns the name of the type in the same format as
+// the `reflect` package does, including type arguments of generic types.
+func typeName(t *types.Named) string {
+	args := t.TypeArgs()
+	if args.Len() == 0 {
+		return t.Obj().Name()
+	}
+
+	qualifier := func(p *types.Package) string {
+		return p.Path()
+	}
+
+	names := make([]string, args.Len())
+	for i := 0; i < args.Len(); i++ {
+		names[i] = types.TypeString(args.At(i), qualifier)
+	}
+
+	return fmt.Sprintf("%s[%s]", t.Obj().Name(), strings.Join(names, ","))
+}
@@ -0,0 +1,669 @@
+package scraper_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/scraper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticScraper_ScrapeType_has_info_interface(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	var tests = []struct {
+		name                 string
+		typeName             string
+		expectedComponentIDs map[string]struct{}
+		expectedRelations    map[string][]string
+	}{
+		{
+			name:                 "empty root",
+			typeName:             "RootEmpty",
+			expectedComponentIDs: map[string]struct{}{},
+			expectedRelations:    map[string][]string{},
+		},
+		{
+			name:                 "root with circular dependencies",
+			typeName:             "RootWithCircularDependencies",
+			expectedComponentIDs: map[string]struct{}{},
+			expectedRelations:    map[string][]string{},
+		},
+		{
+			name:     "root has info with circular pointer dependencies",
+			typeName: "RootHasInfoWithCircularPointerDependencies",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("RootHasInfoWithCircularPointerDependencies"): {},
+			},
+			expectedRelations: map[string][]string{
+				componentID("RootHasInfoWithCircularPointerDependencies"): {
+					componentID("RootHasInfoWithCircularPointerDependencies"),
+				},
+			},
+		},
+		{
+			name:     "root that pointer implements HasInfo interface",
+			typeName: "RootEmptyPtrHasInfo",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("RootEmptyPtrHasInfo"): {},
+			},
+			expectedRelations: map[string][]string{},
+		},
+		{
+			name:     "root with private pointer to private component that implements HasInfo interface",
+			typeName: "RootWithPrivatePointerToPrivateComponentHasInfo",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("privateComponentHasInfo"): {},
+			},
+			expectedRelations: map[string][]string{},
+		},
+		{
+			name:     "root with private map of pointers to public component that implements HasInfo interface",
+			typeName: "RootWithPrivateMapOfPointersToPublicComponentHasInfo",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("PublicComponentHasInfo"): {},
+			},
+			expectedRelations: map[string][]string{},
+		},
+		{
+			name:     "root with public array of private components that implement HasInfo interface",
+			typeName: "RootWithPublicArrayOfPrivateComponentHasInfoValue",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("privateComponentHasInfo"): {},
+			},
+			expectedRelations: map[string][]string{},
+		},
+		{
+			name:                 "root with public interface",
+			typeName:             "RootWithPublicPublicInterface",
+			expectedComponentIDs: map[string]struct{}{},
+			expectedRelations:    map[string][]string{},
+		},
+		{
+			name:     "root has info with nested components",
+			typeName: "RootHasInfoWithNestedComponents",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("RootHasInfoWithNestedComponents"):        {},
+				componentID("RootHasInfoWithComponentHasInfoPointer"): {},
+				componentID("PublicComponentHasInfo"):                 {},
+			},
+			expectedRelations: map[string][]string{
+				componentID("RootHasInfoWithNestedComponents"): {
+					componentID("RootHasInfoWithComponentHasInfoPointer"),
+				},
+				componentID("RootHasInfoWithComponentHasInfoPointer"): {
+					componentID("PublicComponentHasInfo"),
+				},
+			},
+		},
+		{
+			name:     "root with public function returning components that implement HasInfo interface",
+			typeName: "RootWithPublicFunctionReturningComponentsImplementingOfHasInfoInterfaces",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("PublicComponentHasInfo"):  {},
+				componentID("privateComponentHasInfo"): {},
+			},
+			expectedRelations: map[string][]string{},
+		},
+		{
+			name:     "root with public pointer method with argument that implement HasInfo interface",
+			typeName: "RootWithPublicPtrMethodWithHasInfoArgument",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("RootWithPublicPtrMethodWithHasInfoArgument"): {},
+				componentID("RootEmptyHasInfo"):                           {},
+			},
+			expectedRelations: map[string][]string{
+				componentID("RootWithPublicPtrMethodWithHasInfoArgument"): {
+					componentID("RootEmptyHasInfo"),
+				},
+			},
+		},
+		{
+			name:     "root with interface with public method with result type that implement HasInfo interface",
+			typeName: "RootWithInterfaceWithPublicMethodWithHasInfoReturnTypeProperty",
+			expectedComponentIDs: map[string]struct{}{
+				componentID("RootWithInterfaceWithPublicMethodWithHasInfoReturnTypeProperty"): {},
+			},
+			expectedRelations: map[string][]string{},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := scraper.NewStaticScraper(c)
+			result, err := s.ScrapeType(testPKG, tt.typeName)
+			require.NoError(t, err)
+			requireEqualComponentIDs(t, tt.expectedComponentIDs, result.Components)
+			requireEqualRelations(t, tt.expectedRelations, result.Relations)
+		})
+	}
+}
+
+func TestStaticScraper_ScrapeType_has_info_interface_component_info(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	s := scraper.NewStaticScraper(c)
+
+	result, err := s.ScrapeType(testPKG, "RootEmptyPtrHasInfo")
+	require.NoError(t, err)
+
+	expectedComponents := map[string]model.Component{
+		componentID("RootEmptyPtrHasInfo"): {
+			ID:          componentID("RootEmptyPtrHasInfo"),
+			Kind:        "component",
+			Name:        "test.RootEmptyPtrHasInfo",
+			Description: "root description",
+			Technology:  "root technology",
+			Tags:        []string{"root tag 1", "root tag 2"},
+		},
+	}
+	requireEqualComponents(t, expectedComponents, result.Components)
+}
+
+func TestStaticScraper_ScrapeType_rules(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp("^test.(PublicComponent|PublicInterface)$").
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name, "match description")
+		}).
+		Build()
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name               string
+		typeName           string
+		expectedComponents map[string]model.Component
+	}{
+		{
+			name:     "rule matching struct",
+			typeName: "RootWithPublicPointerToPublicComponent",
+			expectedComponents: map[string]model.Component{
+				componentID("PublicComponent"): {
+					ID:          componentID("PublicComponent"),
+					Kind:        "component",
+					Name:        "test.PublicComponent",
+					Description: "match description",
+					Tags:        []string{},
+				},
+			},
+		},
+		{
+			name:     "rule matching interface",
+			typeName: "RootWithPrivatePublicInterface",
+			expectedComponents: map[string]model.Component{
+				componentID("PublicInterface"): {
+					ID:          componentID("PublicInterface"),
+					Kind:        "component",
+					Name:        "test.PublicInterface",
+					Description: "match description",
+					Tags:        []string{},
+				},
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := scraper.NewStaticScraper(c)
+			err := s.RegisterRule(r)
+			require.NoError(t, err)
+
+			result, err := s.ScrapeType(testPKG, tt.typeName)
+			require.NoError(t, err)
+			requireEqualComponents(t, tt.expectedComponents, result.Components)
+		})
+	}
+}
+
+func TestStaticScraper_ScrapeType_relations(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	s := scraper.NewStaticScraper(c)
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithNestedComponents")
+	require.NoError(t, err)
+
+	expectedRelations := []model.Relation{
+		{
+			SourceID: componentID("RootHasInfoWithNestedComponents"),
+			TargetID: componentID("RootHasInfoWithComponentHasInfoPointer"),
+			Path:     "RootHasInfoWithNestedComponents.SubRoot",
+			Kind:     model.RelationKindField,
+		},
+		{
+			SourceID: componentID("RootHasInfoWithComponentHasInfoPointer"),
+			TargetID: componentID("PublicComponentHasInfo"),
+			Path:     "RootHasInfoWithComponentHasInfoPointer.Ptr",
+			Kind:     model.RelationKindField,
+		},
+	}
+	for _, r := range expectedRelations {
+		require.Equal(t, r, result.Relations[r.SourceID][r.TargetID])
+	}
+}
+
+func TestStaticScraper_ScrapeType_explicit_relations(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	s := scraper.NewStaticScraper(c)
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithRelations")
+	require.NoError(t, err)
+
+	expectedComponents := map[string]model.Component{
+		componentID("RootHasInfoWithRelations"): {
+			ID:          componentID("RootHasInfoWithRelations"),
+			Kind:        "component",
+			Name:        "test.RootHasInfoWithRelations",
+			Description: "public",
+			Tags:        []string{},
+		},
+		externalComponentID("test.Database"): {
+			ID:          externalComponentID("test.Database"),
+			Kind:        "component",
+			Name:        "test.Database",
+			Description: "database",
+			Technology:  "PostgreSQL",
+			Tags:        []string{"DB"},
+		},
+		externalComponentID("test.Queue"): {
+			ID:          externalComponentID("test.Queue"),
+			Kind:        "component",
+			Name:        "test.Queue",
+			Description: "queue",
+			Technology:  "Kafka",
+			Tags:        []string{},
+		},
+	}
+	requireEqualComponents(t, expectedComponents, result.Components)
+
+	expectedRelations := []model.Relation{
+		{
+			SourceID:   componentID("RootHasInfoWithRelations"),
+			TargetID:   externalComponentID("test.Database"),
+			Label:      "reads and writes",
+			Kind:       model.RelationKindExplicit,
+			Technology: "SQL",
+			Tags:       []string{},
+		},
+		{
+			SourceID: componentID("RootHasInfoWithRelations"),
+			TargetID: externalComponentID("test.Queue"),
+			Label:    "publishes events",
+			Kind:     model.RelationKindExplicit,
+			Tags:     []string{"ASYNC"},
+		},
+	}
+	for _, r := range expectedRelations {
+		require.Equal(t, r, result.Relations[r.SourceID][r.TargetID])
+	}
+}
+
+func TestStaticScraper_ScrapeType_generics(t *testing.T) {
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.GenericRepository$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name               string
+		generics           scraper.GenericsMode
+		expectedComponents map[string]string
+	}{
+		{
+			name:     "separate instantiations",
+			generics: scraper.GenericsSeparate,
+			expectedComponents: map[string]string{
+				componentID("RootWithGenericRepositories"):                            "test.RootWithGenericRepositories",
+				componentIDf("GenericRepository[%s.PublicComponent]", testPKG):        "test.GenericRepository",
+				componentIDf("GenericRepository[%s.PublicComponentHasInfo]", testPKG): "test.GenericRepository",
+				componentID("PublicComponentHasInfo"):                                 "test.PublicComponentHasInfo",
+			},
+		},
+		{
+			name:     "merged instantiations",
+			generics: scraper.GenericsMerge,
+			expectedComponents: map[string]string{
+				componentID("RootWithGenericRepositories"): "test.RootWithGenericRepositories",
+				componentID("GenericRepository"):           "test.GenericRepository",
+				componentID("PublicComponentHasInfo"):      "test.PublicComponentHasInfo",
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+			c.Generics = tt.generics
+
+			s := scraper.NewStaticScraper(c)
+			err := s.RegisterRule(r)
+			require.NoError(t, err)
+
+			result, err := s.ScrapeType(testPKG, "RootWithGenericRepositories")
+			require.NoError(t, err)
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+		})
+	}
+}
+
+func TestStaticScraper_ScrapeType_anonymous_structs(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.RootHasInfoWithAnonymousStructs\.nested\.inner$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewStaticScraper(c)
+	err = s.RegisterRule(r)
+	require.NoError(t, err)
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithAnonymousStructs")
+	require.NoError(t, err)
+
+	expectedComponents := map[string]string{
+		componentID("RootHasInfoWithAnonymousStructs"):              "test.RootHasInfoWithAnonymousStructs",
+		componentID("RootHasInfoWithAnonymousStructs.nested.inner"): "test.RootHasInfoWithAnonymousStructs.nested.inner",
+		componentID("PublicComponentHasInfo"):                       "test.PublicComponentHasInfo",
+	}
+	requireEqualComponentNames(t, expectedComponents, result.Components)
+
+	r1 := result.Relations[componentID("RootHasInfoWithAnonymousStructs")][componentID("PublicComponentHasInfo")]
+	require.Equal(t, "RootHasInfoWithAnonymousStructs.config.Component", r1.Path)
+}
+
+func TestStaticScraper_ScrapeType_rule_matchers(t *testing.T) {
+	for _, tt := range matcherTests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+
+			r, err := scraper.NewRule().
+				WithMatcher(tt.matcher).
+				WithApplyFunc(func(name string, groups ...string) model.Info {
+					return model.ComponentInfo(name)
+				}).
+				Build()
+			require.NoError(t, err)
+
+			s := scraper.NewStaticScraper(c)
+			require.NoError(t, s.RegisterRule(r))
+
+			result, err := s.ScrapeType(testPKG, "RootHasInfoWithMatchedFields")
+			require.NoError(t, err)
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+			requireEqualRelations(t, tt.expectedRelations, result.Relations)
+		})
+	}
+}
+
+func TestStaticScraper_ScrapeType_context_rules(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.PublicClosingComponent$`).
+		WithContextApplyFunc(func(ctx scraper.MatchContext, groups ...string) model.Info {
+			require.Nil(t, ctx.Type)
+			return model.Info{
+				Name:        ctx.Name,
+				Description: fmt.Sprintf("%s used by %s at level %d", ctx.Field, ctx.Parent.Name, ctx.Level),
+			}
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewStaticScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithMatchedFields")
+	require.NoError(t, err)
+
+	closer := result.Components[componentID("PublicClosingComponent")]
+	require.Equal(t, "Closer used by test.RootHasInfoWithMatchedFields at level 1", closer.Description)
+}
+
+func TestStaticScraper_ScrapeType_panics(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithNameRegexp(`^test\.PublicClosingComponent$`).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			panic("rule failed")
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewStaticScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithMatchedFields")
+	require.Equal(t, scraper.Errors{
+		scraper.PanicError{
+			Type:   "test.PublicClosingComponent",
+			Path:   "RootHasInfoWithMatchedFields.Closer",
+			Source: "rule #0",
+			Value:  "rule failed",
+		},
+	}, err)
+	require.Contains(t, result.Components, componentID("RootHasInfoWithMatchedFields"))
+}
+
+func TestStaticScraper_ScrapeType_hooks(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	s := scraper.NewStaticScraper(c)
+	require.NoError(t, s.OnComponent(func(c *model.Component, ctx scraper.HookContext) bool {
+		c.Tags = append(c.Tags, "field:"+ctx.Field)
+		return ctx.Name != "test.RootHasInfoWithComponentHasInfoValue"
+	}))
+	require.NoError(t, s.OnRelation(func(r *model.Relation, ctx scraper.HookContext) bool {
+		r.Label = ctx.Path
+		return true
+	}))
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels")
+	require.NoError(t, err)
+
+	rootID := componentID("RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels")
+	valueID := componentID("PublicComponentHasInfo")
+	require.Len(t, result.Components, 2)
+	require.Equal(t, []string{"field:Value"}, result.Components[valueID].Tags)
+	require.Equal(t, "RootHasInfoWithPublicPublicComponentHasInfoValueAtMultipleLevels.Value", result.Relations[rootID][valueID].Label)
+}
+
+func TestStaticScraper_ScrapeType_annotations(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	result, err := scraper.NewStaticScraper(c).ScrapeType(testPKG, "RootHasInfoWithAnnotatedFields")
+
+	var annotationErr scraper.AnnotationError
+	require.ErrorAs(t, err, &annotationErr)
+	require.Equal(t, "RootHasInfoWithAnnotatedFields.Invalid", annotationErr.Path)
+
+	requireEqualComponents(t, annotatedComponents, result.Components)
+}
+
+func TestStaticScraper_ScrapeType_annotated_non_struct_fields(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	result, err := scraper.NewStaticScraper(c).ScrapeType(testPKG, "RootHasInfoWithAnnotatedNonStructFields")
+	require.NoError(t, err)
+	requireEqualComponents(t, annotatedNonStructComponents, result.Components)
+}
+
+func TestStaticScraper_ScrapeType_instances(t *testing.T) {
+	for _, tt := range instancesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := scraper.NewConfiguration(
+				testPKG,
+			)
+			c.Instances = tt.mode
+
+			result, err := scraper.NewStaticScraper(c).ScrapeType(testPKG, "RootHasInfoWithInstances")
+			require.NoError(t, err)
+			requireEqualComponentNames(t, tt.expectedComponents, result.Components)
+			requireEqualRelations(t, tt.expectedRelations, result.Relations)
+		})
+	}
+}
+
+func TestStaticScraper_ScrapeType_instances_recursive(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.Instances = scraper.InstancesSeparate
+
+	result, err := scraper.NewStaticScraper(c).ScrapeType(testPKG, "RootHasInfoWithRecursiveComponentHasInfo")
+	require.NoError(t, err)
+	requireEqualComponentNames(t, recursiveInstancesComponents, result.Components)
+	requireEqualRelations(t, recursiveInstancesRelations, result.Relations)
+
+	result, err = scraper.NewStaticScraper(c).ScrapeType(testPKG, "RootHasInfoWithRecursiveComponent")
+	require.NoError(t, err)
+	require.Len(t, result.Components, 1)
+}
+
+func TestStaticScraper_ScrapeTypeWithReport(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewRule().
+		WithMatcher(scraper.KindOf(reflect.Func)).
+		WithApplyFunc(func(name string, groups ...string) model.Info {
+			return model.ComponentInfo(name)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewStaticScraper(c)
+	require.NoError(t, s.RegisterRule(r))
+
+	_, report, err := s.ScrapeTypeWithReport(testPKG, "RootHasInfoWithMatchedFields")
+	require.NoError(t, err)
+
+	require.Contains(t, report.VisitedTypes, testPKG+".RootHasInfoWithMatchedFields")
+	require.Contains(t, report.SkippedPackages, "github.com/krzysztofreczek/go-structurizr/pkg/model")
+	require.Contains(t, report.UnmatchedTypes, testPKG+".PublicClosingComponent")
+	require.Equal(t, 1, report.Rules[0].Hits)
+	require.Empty(t, report.UnmatchedRules())
+	require.Positive(t, report.Elapsed)
+}
+
+func TestStaticScraper_ScrapeType_ignore_rules(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+
+	r, err := scraper.NewIgnoreRule().
+		WithNameRegexp(`^test\.RootHasInfoWithComponentHasInfoPointer$`).
+		WithMode(scraper.IgnoreComponent).
+		Build()
+	require.NoError(t, err)
+
+	s := scraper.NewStaticScraper(c)
+	err = s.RegisterIgnoreRule(r)
+	require.NoError(t, err)
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithNestedComponents")
+	require.NoError(t, err)
+
+	expectedComponents := map[string]string{
+		componentID("RootHasInfoWithNestedComponents"): "test.RootHasInfoWithNestedComponents",
+		componentID("PublicComponentHasInfo"):          "test.PublicComponentHasInfo",
+	}
+	requireEqualComponentNames(t, expectedComponents, result.Components)
+
+	c.ExcludedPackages = []string{testPKG}
+
+	result, err = scraper.NewStaticScraper(c).ScrapeType(testPKG, "RootHasInfoWithNestedComponents")
+	require.NoError(t, err)
+	require.Empty(t, result.Components)
+}
+
+func TestStaticScraper_ScrapeType_id_collision(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	c.IDStrategy = func(pkg string, typeName string) string {
+		return "ID"
+	}
+	s := scraper.NewStaticScraper(c)
+
+	result, err := s.ScrapeType(testPKG, "RootHasInfoWithNestedComponents")
+
+	var collision scraper.IDCollisionError
+	require.ErrorAs(t, err, &collision)
+	require.Len(t, result.Components, 1)
+}
+
+func TestStaticScraper_ScrapeType_errors(t *testing.T) {
+	c := scraper.NewConfiguration(
+		testPKG,
+	)
+	var tests = []struct {
+		name     string
+		pkg      string
+		typeName string
+	}{
+		{
+			name:     "unknown package",
+			pkg:      "github.com/krzysztofreczek/go-structurizr/pkg/foo",
+			typeName: "RootEmpty",
+		},
+		{
+			name:     "unknown type",
+			pkg:      testPKG,
+			typeName: "Foo",
+		},
+		{
+			name:     "uninstantiated generic type",
+			pkg:      testPKG,
+			typeName: "RootGenericHasInfoWithGenericProperty",
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := scraper.NewStaticScraper(c)
+			_, err := s.ScrapeType(tt.pkg, tt.typeName)
+			require.Error(t, err)
+		})
+	}
+}
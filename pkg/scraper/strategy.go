@@ -3,20 +3,85 @@ package scraper
 import (
 	"fmt"
 	"reflect"
-	"strings"
 	"unsafe"
 
-	"github.com/krzysztofreczek/go-structurizr/pkg/internal"
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
 )
 
 var (
+	// maxRecursiveScrapes is the recursion limit used if Limits.MaxRecursion
+	// is not configured.
 	maxRecursiveScrapes = 100
 )
 
+// origin describes how the scraped value has been reached
+// from the closest parent component.
+//
+// It also tracks the closest named type the value is declared in,
+// which identifies anonymous structs, and the name, the struct tag
+// and the annotation of the field the value has been reached through.
+// The instance key is the one returned by the value itself, if any.
+type origin struct {
+	parentID   string
+	path       string
+	kind       string
+	ownerPkg   string
+	ownerPath  string
+	field      string
+	tag        reflect.StructTag
+	annotation *annotation
+	instance   string
+}
+
+func newOrigin(parentID string, name string) origin {
+	return origin{
+		parentID: parentID,
+		path:     name,
+	}
+}
+
+func (o origin) via(segment string, kind string) origin {
+	return origin{
+		parentID:  o.parentID,
+		path:      o.path + segment,
+		kind:      kind,
+		ownerPkg:  o.ownerPkg,
+		ownerPath: o.ownerPath + segment,
+	}
+}
+
+// viaField returns the origin of the value of the struct field.
+func (o origin) viaField(name string, tag reflect.StructTag) origin {
+	fo := o.via("."+name, model.RelationKindField)
+	fo.field = name
+	fo.tag = tag
+	return fo
+}
+
+// element returns the origin of the element of the slice or the map,
+// which keeps the field the collection has been reached through.
+func (o origin) element(kind string) origin {
+	eo := o.via("[]", kind)
+	eo.field = o.field
+	eo.tag = o.tag
+	eo.annotation = o.annotation
+	return eo
+}
+
+func (o origin) withInstance(instance string) origin {
+	o.instance = instance
+	return o
+}
+
+func (o origin) ownedBy(pkg string, typeName string) origin {
+	o.ownerPkg = pkg
+	o.ownerPath = typeName
+	return o
+}
+
 func (s *scraper) scrape(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	if !v.IsValid() {
@@ -24,13 +89,72 @@ func (s *scraper) scrape(
 		return
 	}
 
+	if s.isAborted() {
+		return
+	}
+	if s.isTooDeep(o, level) {
+		s.debug(v, "value exceeds the depth limit, skipping")
+		return
+	}
+
+	s.visit(v, o)
+
+	defer s.enter(strategyName(v.Kind()), o, level)()
+
+	o, ok := s.scrapeNamedValue(v, o, level)
+	if !ok {
+		return
+	}
+
 	strategy := s.resolveScrapingStrategy(v)
-	strategy(v, parentID, level)
+	strategy(v, o, level)
+}
+
+// scrapeNamedValue resolves components of named types other than structs
+// and interfaces, e.g. functions or maps, matched by rules with matchers.
+//
+// It returns the origin the value should be further scraped with,
+// and whether it should be scraped at all.
+func (s *scraper) scrapeNamedValue(v reflect.Value, o origin, level int) (origin, bool) {
+	t := v.Type()
+	if t.Name() == "" || isComponentKind(t.Kind()) || t.Kind() == reflect.Ptr {
+		return o, true
+	}
+
+	pkg, name := valuePackage(v), s.valueTypeName(v)
+	if !s.isPackageScrappable(pkg) {
+		s.reportSkippedPackage(pkg)
+		if c := s.addAnnotatedComponent(pkg, name, o); c.ID != "" {
+			return newOrigin(c.ID, shortTypeName(name)), true
+		}
+		return o, true
+	}
+
+	s.reportVisit(pkg, name)
+
+	mode, ignored := s.ignoreMode(pkg, componentName(pkg, name))
+	if ignored {
+		s.debug(v, "value is ignored by one of the ignore rules")
+		return o, mode != IgnoreAll
+	}
+
+	o = o.withInstance(s.getInstanceIDFromInterface(v, o))
+
+	var c model.Component
+	if info, ok := s.getInfoFromRules(v, pkg, name, o, level); ok {
+		c = s.addComponent(pkg, name, info, o)
+	} else {
+		c = s.addAnnotatedComponent(pkg, name, o)
+	}
+	if c.ID == "" {
+		return o, true
+	}
+	return newOrigin(c.ID, shortTypeName(name)), true
 }
 
 type scrapingStrategy func(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 )
 
@@ -55,7 +179,7 @@ func (s *scraper) resolveScrapingStrategy(v reflect.Value) scrapingStrategy {
 
 func (s *scraper) scrapeInterfaceStrategy(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	s.debug(v, "interface scraping strategy applied: if the interface is not nil, the value will be scraped, otherwise scraper will try to resolve info data from the interface type")
@@ -63,9 +187,27 @@ func (s *scraper) scrapeInterfaceStrategy(
 	if !v.Elem().IsValid() {
 		s.debug(v, "scraping the interface type")
 
-		info, ok := s.getInfoFromRules(v)
-		if ok {
-			_ = s.addComponent(v, info, parentID)
+		pkg, name := valuePackage(v), s.valueTypeName(v)
+		if s.isPackageExcluded(pkg) {
+			s.debug(v, "value package '%s' is excluded from scraping", pkg)
+			s.reportSkippedPackage(pkg)
+			return
+		}
+		s.reportVisit(pkg, name)
+
+		if _, ignored := s.ignoreMode(pkg, componentName(pkg, name)); ignored {
+			s.debug(v, "interface type is ignored by one of the ignore rules")
+			return
+		}
+
+		info, ok := s.getInfoFromRules(v, pkg, name, o, level)
+		switch {
+		case ok:
+			_ = s.addComponent(pkg, name, info, o)
+		case o.annotation != nil:
+			_ = s.addAnnotatedComponent(pkg, name, o)
+		default:
+			s.reportUnmatched(pkg, name)
 		}
 
 		return
@@ -74,12 +216,12 @@ func (s *scraper) scrapeInterfaceStrategy(
 	s.debug(v, "scraping the value implementing the interface")
 	v = v.Elem()
 
-	s.scrape(v, parentID, level)
+	s.scrape(v, o, level)
 }
 
 func (s *scraper) scrapePointerStrategy(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	s.debug(v, "pointer scraping strategy applied: if the pointer is not nil, the value will be scraped")
@@ -90,40 +232,44 @@ func (s *scraper) scrapePointerStrategy(
 		v = reflect.New(v.Type().Elem()).Elem()
 	}
 
-	s.scrape(v, parentID, level)
+	s.scrape(v, o, level)
 }
 
 func (s *scraper) scrapeMapStrategy(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	s.debug(v, "map scraping strategy applied: each of map elements will be scraped")
 
+	n := s.elementsCount(v.Len(), o)
+
 	iterator := v.MapRange()
-	for {
+	for i := 0; i < n; i++ {
 		if !iterator.Next() {
 			break
 		}
-		s.scrape(iterator.Value(), parentID, level)
+		s.scrape(iterator.Value(), o.element(model.RelationKindMapValue), level)
 	}
 }
 
 func (s *scraper) scrapeIterableStrategy(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	s.debug(v, "iterable scraping strategy applied: each of elements will be scraped")
 
-	for i := 0; i < v.Len(); i++ {
-		s.scrape(v.Index(i), parentID, level)
+	n := s.elementsCount(v.Len(), o)
+
+	for i := 0; i < n; i++ {
+		s.scrape(v.Index(i), o.element(model.RelationKindSliceElement), level)
 	}
 }
 
 func (s *scraper) scrapeFunc(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	s.debug(v, "function scraping strategy applied: input and output types will be scraped")
@@ -132,18 +278,18 @@ func (s *scraper) scrapeFunc(
 
 	for i := 0; i < t.NumIn(); i++ {
 		v = reflect.New(t.In(i))
-		s.scrape(v, parentID, level)
+		s.scrape(v, o.via("()", model.RelationKindMethodArg), level)
 	}
 
 	for i := 0; i < t.NumOut(); i++ {
 		v = reflect.New(t.Out(i))
-		s.scrape(v, parentID, level)
+		s.scrape(v, o.via("()", model.RelationKindMethodReturn), level)
 	}
 }
 
 func (s *scraper) scrapeNoop(
 	v reflect.Value,
-	_ string,
+	_ origin,
 	_ int,
 ) {
 	s.debug(v, "value will not be scraped")
@@ -151,133 +297,194 @@ func (s *scraper) scrapeNoop(
 
 func (s *scraper) scrapeStruct(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
 	s.debug(v, "struct scraping strategy applied: value and each of its properties (both exported and private) and methods (only exported) will be scraped")
 
-	if !s.isScrappable(v) {
+	pkg, name := s.structType(v, o)
+	if !s.isScrappable(v, pkg) {
+		s.reportSkippedPackage(pkg)
+		_ = s.addAnnotatedComponent(pkg, name, o)
 		return
 	}
+	s.reportVisit(pkg, name)
 
-	vID := componentID(v)
-	vUsageKey := fmt.Sprintf("%s-%s", parentID, vID)
-	if c, ok := s.typeCounters[vUsageKey]; ok && c > maxRecursiveScrapes {
+	mode, ignored := s.ignoreMode(pkg, componentName(pkg, name))
+	if ignored && mode == IgnoreAll {
+		s.debug(v, "struct is ignored by one of the ignore rules, skipping")
+		return
+	}
+
+	vID := s.componentID(pkg, name)
+	vUsageKey := fmt.Sprintf("%s-%s", o.parentID, vID)
+	if s.isRecursionExceeded(vUsageKey, o) {
 		s.debug(v, "struct is being used recursively, skipping")
+		s.reportRecursionCutoff(pkg, name)
 		return
-	} else {
-		s.typeCounters[vUsageKey]++
 	}
+	s.typeCounters[vUsageKey]++
 
 	var c model.Component
+	o = o.withInstance(s.getInstanceIDFromInterface(v, o))
 
-	info, ok := s.getInfoFromInterface(v)
-	if ok {
-		c = s.addComponent(v, info, parentID)
-	}
+	if !ignored {
+		info, ok := s.getInfoFromInterface(v, o)
+		if ruleInfo, matched := s.getInfoFromRules(v, pkg, name, o, level); matched {
+			info, ok = ruleInfo, true
+		}
+		if ok {
+			c = s.addComponent(pkg, name, info, o)
+		}
 
-	info, ok = s.getInfoFromRules(v)
-	if ok {
-		c = s.addComponent(v, info, parentID)
+		if c.ID == "" {
+			c = s.addAnnotatedComponent(pkg, name, o)
+		}
+		if c.ID == "" {
+			s.reportUnmatched(pkg, name)
+		}
+	} else {
+		s.debug(v, "struct is ignored as a component by one of the ignore rules")
 	}
 
 	if c.ID != "" {
-		parentID = c.ID
+		s.addExplicitRelations(c, s.getRelationsFromInterface(v, o))
+		o = newOrigin(c.ID, shortTypeName(name))
+	}
+	if v.Type().Name() != "" {
+		o = o.ownedBy(pkg, shortTypeName(name))
 	}
 
-	s.scrapeValueFields(v, parentID, level)
-	s.scrapeValueMethods(v, parentID, level)
-	s.scrapeValueMethods(reflect.New(v.Type()), parentID, level)
+	s.scrapeValueFields(v, o, level)
+	s.scrapeValueMethods(v, o, level)
+	s.scrapeValueMethods(reflect.New(v.Type()), o, level)
 }
 
 func (s *scraper) scrapeValueFields(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
+	t := v.Type()
 	for i := 0; i < v.NumField(); i++ {
-		s.scrape(v.Field(i), parentID, level+1)
+		fo := o.viaField(t.Field(i).Name, t.Field(i).Tag)
+
+		a, skip := s.fieldAnnotation(fo)
+		if skip {
+			s.debug(v.Field(i), "field is skipped by the `%s:\"-\"` struct tag", AnnotationTagKey)
+			continue
+		}
+		fo.annotation = a
+
+		s.scrape(v.Field(i), fo, level+1)
 	}
 }
 
 func (s *scraper) scrapeValueMethods(
 	v reflect.Value,
-	parentID string,
+	o origin,
 	level int,
 ) {
+	t := v.Type()
 	for i := 0; i < v.NumMethod(); i++ {
-		s.scrape(v.Method(i), parentID, level+1)
+		mo := o.via("."+t.Method(i).Name, o.kind)
+		s.scrape(v.Method(i), mo, level+1)
 	}
 }
 
-func (s *scraper) addComponent(
-	v reflect.Value,
-	info model.Info,
-	parentID string,
-) model.Component {
-	c := model.Component{
-		ID:          componentID(v),
-		Kind:        info.Kind,
-		Name:        info.Name,
-		Description: info.Description,
-		Technology:  info.Technology,
-		Tags:        info.Tags,
-	}
-	s.structure.AddComponent(c, parentID)
-	return c
-}
-
-func (s *scraper) isScrappable(v reflect.Value) bool {
-	vPkg := valuePackage(v)
-	for _, pkg := range s.config.Packages {
-		if strings.HasPrefix(vPkg, pkg) {
-			s.debug(v, "value package '%s' is applicable for scraping", vPkg)
-			return true
-		}
+func (s *scraper) isScrappable(v reflect.Value, pkg string) bool {
+	if s.isPackageScrappable(pkg) {
+		s.debug(v, "value package '%s' is applicable for scraping", pkg)
+		return true
 	}
 
-	s.debug(v, "value package '%s' IS NOT applicable for scraping", vPkg)
+	s.debug(v, "value package '%s' IS NOT applicable for scraping", pkg)
 	return false
 }
 
-func (s *scraper) getInfoFromInterface(v reflect.Value) (model.Info, bool) {
-	var info model.HasInfo
-	var ok bool
-
-	if v.CanAddr() {
-		// it allows accessing new pointer by the interface
-		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
-		// v.Addr() instead of v supports both value and pointer receiver
-		info, ok = v.Addr().Interface().(model.HasInfo)
-	} else if v.CanInterface() {
-		info, ok = v.Interface().(model.HasInfo)
-	} else {
-		// it allows accessing new instance by the interface
-		v = reflect.New(v.Type())
-		info, ok = v.Interface().(model.HasInfo)
+// structType returns the package and the name of the struct type.
+// Anonymous structs are named after the closest named type they are
+// declared in and the path leading to them, e.g. `Root.config`.
+func (s *scraper) structType(v reflect.Value, o origin) (string, string) {
+	if v.Type().Name() == "" {
+		return o.ownerPkg, o.ownerPath
 	}
+	return valuePackage(v), s.valueTypeName(v)
+}
 
+// getInfoFromInterface returns the info of the value implementing
+// `model.HasInfo`. Panics of the `Info()` method are recorded as errors.
+func (s *scraper) getInfoFromInterface(v reflect.Value, o origin) (model.Info, bool) {
+	info, ok := interfaceOf(v).(model.HasInfo)
 	if !ok || info == nil {
 		return model.Info{}, false
 	}
 
+	defer s.recoverPanic(s.valueComponentName(v), "Info()", o.path)
+
 	i := info.Info()
 	s.debug(v, "resolved info data %+v from .Info() method", i)
 
 	return i, true
 }
 
-func (s *scraper) getInfoFromRules(v reflect.Value) (model.Info, bool) {
-	vPkg := valuePackage(v)
-	name := componentName(v)
-	for _, r := range s.rules {
-		if !r.Applies(vPkg, name) {
-			continue
-		}
+// getInstanceIDFromInterface returns the key identifying the instance
+// in the InstancesSeparate mode, if the value implements `model.HasInstanceID`.
+func (s *scraper) getInstanceIDFromInterface(v reflect.Value, o origin) string {
+	if s.config.Instances != InstancesSeparate {
+		return ""
+	}
 
-		i := r.Apply(name)
-		s.debug(v, "resolved info data %+v from one of the rules", i)
+	instance, ok := interfaceOf(v).(model.HasInstanceID)
+	if !ok || instance == nil {
+		return ""
+	}
+
+	defer s.recoverPanic(s.valueComponentName(v), "InstanceID()", o.path)
+
+	id := instance.InstanceID()
+	s.debug(v, "resolved instance ID '%s' from .InstanceID() method", id)
+
+	return id
+}
 
+func (s *scraper) getRelationsFromInterface(v reflect.Value, o origin) []model.RelationInfo {
+	relations, ok := interfaceOf(v).(model.HasRelations)
+	if !ok || relations == nil {
+		return nil
+	}
+
+	defer s.recoverPanic(s.valueComponentName(v), "Relations()", o.path)
+
+	r := relations.Relations()
+	s.debug(v, "resolved relations %+v from .Relations() method", r)
+
+	return r
+}
+
+// interfaceOf returns the interface of the value that supports detection
+// of methods implemented with both value and pointer receivers.
+func interfaceOf(v reflect.Value) interface{} {
+	if v.CanAddr() {
+		// it allows accessing new pointer by the interface
+		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+		// v.Addr() instead of v supports both value and pointer receiver
+		return v.Addr().Interface()
+	}
+
+	if v.CanInterface() {
+		return v.Interface()
+	}
+
+	// it allows accessing new instance by the interface
+	return reflect.New(v.Type()).Interface()
+}
+
+func (s *scraper) getInfoFromRules(v reflect.Value, pkg string, typeName string, o origin, level int) (model.Info, bool) {
+	ctx := s.runtimeMatchContext(v.Type(), pkg, componentName(pkg, typeName), o, level)
+	i, ok := s.applyRules(ctx)
+	if ok {
+		s.debug(v, "resolved info data %+v from one of the rules", i)
 		return i, true
 	}
 
@@ -285,15 +492,16 @@ func (s *scraper) getInfoFromRules(v reflect.Value) (model.Info, bool) {
 	return model.Info{}, false
 }
 
-func componentID(v reflect.Value) string {
-	id := fmt.Sprintf("%s.%s", valuePackage(v), v.Type().Name())
-	return internal.Hash(id)
+func (s *scraper) valueComponentID(v reflect.Value) string {
+	return s.componentID(valuePackage(v), s.valueTypeName(v))
+}
+
+func (s *scraper) valueComponentName(v reflect.Value) string {
+	return componentName(valuePackage(v), s.valueTypeName(v))
 }
 
-func componentName(v reflect.Value) string {
-	pkg := strings.Split(valuePackage(v), "/")
-	name := fmt.Sprintf("%s.%s", pkg[len(pkg)-1], v.Type().Name())
-	return name
+func (s *scraper) valueTypeName(v reflect.Value) string {
+	return s.typeName(v.Type().Name())
 }
 
 func valuePackage(v reflect.Value) string {
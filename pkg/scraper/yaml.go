@@ -2,21 +2,74 @@ package scraper
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
 	"github.com/krzysztofreczek/go-structurizr/pkg/yaml"
+	"github.com/pkg/errors"
 )
 
-func toScraperConfig(c yaml.Config) Configuration {
-	return NewConfiguration(c.Configuration.Packages...)
+func toScraperConfig(c yaml.Config) (Configuration, error) {
+	config := NewConfiguration(c.Configuration.Packages...)
+	config.ExcludedPackages = c.Configuration.ExcludedPackages
+
+	for _, rgx := range c.Configuration.ExcludedPackageRegexps {
+		r, err := regexp.Compile(rgx)
+		if err != nil {
+			return Configuration{}, errors.Wrapf(err,
+				"could not compile excluded package expression `%s` "+
+					"as correct regular expression", rgx)
+		}
+		config.ExcludedPackageRegexps = append(config.ExcludedPackageRegexps, r)
+	}
+
+	strategy, err := idStrategyByName(c.Configuration.IDStrategy)
+	if err != nil {
+		return Configuration{}, err
+	}
+	config.IDStrategy = strategy
+
+	generics := GenericsMode(c.Configuration.Generics)
+	switch generics {
+	case "", GenericsSeparate, GenericsMerge:
+		config.Generics = generics
+	default:
+		return Configuration{}, errors.Errorf("unknown generics mode `%s`", generics)
+	}
+
+	instances := InstancesMode(c.Configuration.Instances)
+	switch instances {
+	case "", InstancesMerge, InstancesSeparate:
+		config.Instances = instances
+	default:
+		return Configuration{}, errors.Errorf("unknown instances mode `%s`", instances)
+	}
+
+	config.Limits = Limits(c.Configuration.Limits)
+
+	return config, nil
 }
 
 func toScraperRules(c yaml.Config) ([]Rule, error) {
-	rules := make([]Rule, len(c.Rules))
-	for i, r := range c.Rules {
+	rules := make([]Rule, 0, len(c.Rules))
+	for _, r := range c.Rules {
 		r := r
-		rule, err := NewRule().
+		if r.Ignore != "" {
+			continue
+		}
+
+		builder := NewRule()
+		if r.Match != nil {
+			m, err := toScraperMatcher(*r.Match)
+			if err != nil {
+				return nil, err
+			}
+			builder = builder.WithMatcher(m)
+		}
+
+		rule, err := builder.
 			WithNameRegexp(r.NameRegexp).
 			WithPkgRegexps(r.PackageRegexps...).
 			WithApplyFunc(
@@ -48,7 +101,137 @@ func toScraperRules(c yaml.Config) ([]Rule, error) {
 		if err != nil {
 			return nil, err
 		}
-		rules[i] = rule
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// toScraperMatcher converts the YAML matcher into a Matcher.
+// Interfaces are referred to by their full names, e.g. `io.Closer`,
+// hence they are resolved by the static scraper only,
+// see checkRuntimeRules.
+func toScraperMatcher(c yaml.ConfigRuleMatcher) (Matcher, error) {
+	matchers := make([]Matcher, 0)
+
+	if c.Implements != "" {
+		matchers = append(matchers, implementsName(c.Implements))
+	}
+
+	if c.Kind != "" {
+		k, err := kindByName(c.Kind)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, KindOf(k))
+	}
+
+	if c.Method != "" {
+		matchers = append(matchers, HasMethod(c.Method))
+	}
+
+	if c.StructTag != "" {
+		matchers = append(matchers, HasStructTag(c.StructTag))
+	}
+
+	if len(c.All) > 0 {
+		all, err := toScraperMatchers(c.All)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, And(all...))
+	}
+
+	if len(c.Any) > 0 {
+		anyOf, err := toScraperMatchers(c.Any)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, Or(anyOf...))
+	}
+
+	if c.Not != nil {
+		m, err := toScraperMatcher(*c.Not)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, Not(m))
+	}
+
+	if len(matchers) == 0 {
+		return nil, errors.New("rule matcher must define at least one condition")
+	}
+
+	return And(matchers...), nil
+}
+
+func toScraperMatchers(cs []yaml.ConfigRuleMatcher) ([]Matcher, error) {
+	matchers := make([]Matcher, len(cs))
+	for i, c := range cs {
+		m, err := toScraperMatcher(c)
+		if err != nil {
+			return nil, err
+		}
+		matchers[i] = m
+	}
+	return matchers, nil
+}
+
+// checkRuntimeRules returns an error if any of the rules cannot be applied
+// by the runtime scraper. Interfaces referred to by their names with
+// the `implements` condition cannot be resolved with reflection.
+func checkRuntimeRules(c yaml.Config) error {
+	for i, r := range c.Rules {
+		if r.Match != nil && usesImplements(*r.Match) {
+			return errors.Errorf(
+				"rule #%d: `implements` condition is supported by the static scraper only", i)
+		}
+	}
+	return nil
+}
+
+func usesImplements(c yaml.ConfigRuleMatcher) bool {
+	if c.Implements != "" {
+		return true
+	}
+	for _, m := range c.All {
+		if usesImplements(m) {
+			return true
+		}
+	}
+	for _, m := range c.Any {
+		if usesImplements(m) {
+			return true
+		}
+	}
+	return c.Not != nil && usesImplements(*c.Not)
+}
+
+// kindByName returns the reflect.Kind of the given name, e.g. `func`.
+func kindByName(name string) (reflect.Kind, error) {
+	for k := reflect.Bool; k <= reflect.UnsafePointer; k++ {
+		if k.String() == name {
+			return k, nil
+		}
+	}
+	return reflect.Invalid, errors.Errorf("unknown kind `%s`", name)
+}
+
+func toScraperIgnoreRules(c yaml.Config) ([]IgnoreRule, error) {
+	rules := make([]IgnoreRule, 0)
+	for _, r := range c.Rules {
+		if r.Ignore == "" {
+			continue
+		}
+
+		rule, err := NewIgnoreRule().
+			WithNameRegexp(r.NameRegexp).
+			WithPkgRegexps(r.PackageRegexps...).
+			WithMode(IgnoreMode(r.Ignore)).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
 	}
 	return rules, nil
 }
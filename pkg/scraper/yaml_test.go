@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
@@ -16,10 +17,146 @@ func Test_toScraperConfig(t *testing.T) {
 		},
 	}
 
-	c := toScraperConfig(yamlConfiguration)
+	c, err := toScraperConfig(yamlConfiguration)
+	require.NoError(t, err)
 	require.Equal(t, yamlConfiguration.Configuration.Packages, c.Packages)
 }
 
+func Test_toScraperConfig_with_id_strategy(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Configuration: yaml.ConfigConfiguration{
+			IDStrategy: "readable",
+		},
+	}
+
+	c, err := toScraperConfig(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, "PKG_1_Type", c.IDStrategy("PKG_1", "Type"))
+
+	yamlConfiguration.Configuration.IDStrategy = "unknown"
+
+	_, err = toScraperConfig(yamlConfiguration)
+	require.Error(t, err)
+}
+
+func Test_toScraperConfig_with_generics(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Configuration: yaml.ConfigConfiguration{
+			Generics: "merge",
+		},
+	}
+
+	c, err := toScraperConfig(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, GenericsMerge, c.Generics)
+
+	yamlConfiguration.Configuration.Generics = "unknown"
+
+	_, err = toScraperConfig(yamlConfiguration)
+	require.Error(t, err)
+}
+
+func Test_toScraperConfig_with_instances(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Configuration: yaml.ConfigConfiguration{
+			Instances: "separate",
+		},
+	}
+
+	c, err := toScraperConfig(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, InstancesSeparate, c.Instances)
+
+	yamlConfiguration.Configuration.Instances = "unknown"
+
+	_, err = toScraperConfig(yamlConfiguration)
+	require.Error(t, err)
+}
+
+func Test_toScraperConfig_with_limits(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Configuration: yaml.ConfigConfiguration{
+			Limits: yaml.ConfigLimits{
+				MaxDepth:      10,
+				MaxComponents: 500,
+				MaxElements:   20,
+				MaxRecursion:  5,
+			},
+		},
+	}
+
+	c, err := toScraperConfig(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, Limits{
+		MaxDepth:      10,
+		MaxComponents: 500,
+		MaxElements:   20,
+		MaxRecursion:  5,
+	}, c.Limits)
+}
+
+func Test_toScraperConfig_with_exclusions(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Configuration: yaml.ConfigConfiguration{
+			ExcludedPackages:       []string{"PKG_1/mocks"},
+			ExcludedPackageRegexps: []string{"/generated$"},
+		},
+	}
+
+	c, err := toScraperConfig(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, []string{"PKG_1/mocks"}, c.ExcludedPackages)
+	require.Len(t, c.ExcludedPackageRegexps, 1)
+	require.True(t, c.ExcludedPackageRegexps[0].MatchString("PKG_1/generated"))
+
+	yamlConfiguration.Configuration.ExcludedPackageRegexps = []string{"["}
+
+	_, err = toScraperConfig(yamlConfiguration)
+	require.Error(t, err)
+}
+
+func Test_toScraperIgnoreRules(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Rules: []yaml.ConfigRule{
+			{
+				PackageRegexps: []string{"PKG_1"},
+				NameRegexp:     `^test.TestClient$`,
+				Component: yaml.ConfigRuleComponent{
+					Name: "Client",
+				},
+			},
+			{
+				PackageRegexps: []string{"PKG_1"},
+				NameRegexp:     `Logger$`,
+				Ignore:         "all",
+			},
+			{
+				NameRegexp: `Metrics$`,
+				Ignore:     "component",
+			},
+		},
+	}
+
+	rules, err := toScraperRules(yamlConfiguration)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	ignoreRules, err := toScraperIgnoreRules(yamlConfiguration)
+	require.NoError(t, err)
+	require.Len(t, ignoreRules, 2)
+
+	require.True(t, ignoreRules[0].Applies("PKG_1", "test.Logger"))
+	require.False(t, ignoreRules[0].Applies("PKG_2", "test.Logger"))
+	require.Equal(t, IgnoreAll, ignoreRules[0].Mode())
+	require.True(t, ignoreRules[1].Applies("PKG_2", "test.Metrics"))
+	require.Equal(t, IgnoreComponent, ignoreRules[1].Mode())
+
+	yamlConfiguration.Rules[2].Ignore = "unknown"
+
+	_, err = toScraperIgnoreRules(yamlConfiguration)
+	require.Error(t, err)
+}
+
 func Test_toScraperRules(t *testing.T) {
 	yamlConfiguration := yaml.Config{
 		Rules: []yaml.ConfigRule{
@@ -117,3 +254,161 @@ func Test_toScraperRules_with_name_aliases(t *testing.T) {
 	require.Equal(t, expectedRule.Applies(pkg, name), r.Applies(pkg, name))
 	require.Equal(t, expectedRule.Apply(name), r.Apply(name))
 }
+
+func Test_toScraperRules_with_matcher(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		Rules: []yaml.ConfigRule{
+			{
+				NameRegexp: `^test\.`,
+				Match: &yaml.ConfigRuleMatcher{
+					Any: []yaml.ConfigRuleMatcher{
+						{Implements: "io.Closer"},
+						{Kind: "func", Not: &yaml.ConfigRuleMatcher{Method: "String"}},
+						{StructTag: "role"},
+					},
+				},
+				Component: yaml.ConfigRuleComponent{
+					Name: "Matched",
+				},
+			},
+		},
+	}
+
+	rules, err := toScraperRules(yamlConfiguration)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	r := rules[0]
+
+	var tests = []struct {
+		name     string
+		ctx      MatchContext
+		expected bool
+	}{
+		{
+			name: "implements",
+			ctx: MatchContext{
+				Name: "test.Connection",
+				Kind: reflect.Struct,
+				implementsNamed: func(pkg string, name string) bool {
+					return pkg == "io" && name == "Closer"
+				},
+			},
+			expected: true,
+		},
+		{
+			name:     "kind",
+			ctx:      MatchContext{Name: "test.HandlerFunc", Kind: reflect.Func},
+			expected: true,
+		},
+		{
+			name: "kind with excluded method",
+			ctx: MatchContext{
+				Name: "test.HandlerFunc",
+				Kind: reflect.Func,
+				hasMethod: func(name string) bool {
+					return name == "String"
+				},
+			},
+			expected: false,
+		},
+		{
+			name:     "struct tag",
+			ctx:      MatchContext{Name: "test.Store", Kind: reflect.Struct, Tag: `role:"database"`},
+			expected: true,
+		},
+		{
+			name:     "name",
+			ctx:      MatchContext{Name: "other.HandlerFunc", Kind: reflect.Func},
+			expected: false,
+		},
+		{
+			name:     "no condition",
+			ctx:      MatchContext{Name: "test.Store", Kind: reflect.Struct},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, AdaptRule(r).Matches(tt.ctx))
+		})
+	}
+
+	require.Equal(t, "Matched", r.Apply("test.Store").Name)
+}
+
+func Test_toScraperRules_with_invalid_matcher(t *testing.T) {
+	var tests = []struct {
+		name    string
+		matcher yaml.ConfigRuleMatcher
+	}{
+		{
+			name:    "unknown kind",
+			matcher: yaml.ConfigRuleMatcher{Kind: "function"},
+		},
+		{
+			name:    "empty",
+			matcher: yaml.ConfigRuleMatcher{},
+		},
+		{
+			name:    "empty nested",
+			matcher: yaml.ConfigRuleMatcher{All: []yaml.ConfigRuleMatcher{{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.matcher
+			_, err := toScraperRules(yaml.Config{
+				Rules: []yaml.ConfigRule{{Match: &m}},
+			})
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_checkRuntimeRules(t *testing.T) {
+	var tests = []struct {
+		name        string
+		matcher     *yaml.ConfigRuleMatcher
+		expectedErr bool
+	}{
+		{
+			name:    "no matcher",
+			matcher: nil,
+		},
+		{
+			name:    "no implements",
+			matcher: &yaml.ConfigRuleMatcher{Kind: "func", Not: &yaml.ConfigRuleMatcher{Method: "String"}},
+		},
+		{
+			name:        "implements",
+			matcher:     &yaml.ConfigRuleMatcher{Implements: "io.Closer"},
+			expectedErr: true,
+		},
+		{
+			name:        "nested implements",
+			matcher:     &yaml.ConfigRuleMatcher{Any: []yaml.ConfigRuleMatcher{{Kind: "func"}, {Implements: "io.Closer"}}},
+			expectedErr: true,
+		},
+		{
+			name:        "negated implements",
+			matcher:     &yaml.ConfigRuleMatcher{Not: &yaml.ConfigRuleMatcher{Implements: "io.Closer"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRuntimeRules(yaml.Config{
+				Rules: []yaml.ConfigRule{{NameRegexp: ".*", Match: tt.matcher}},
+			})
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package view
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+const (
+	defaultC4Include = "<C4/C4_Component>"
+
+	c4RelationTag = "go-structurizr"
+	c4BoundaryID  = "container"
+
+	snippetC4Head = `This diagram has been generated with go-structurizr 
+[https://github.com/krzysztofreczek/go-structurizr]
+
+@startuml
+!include {{c4_include}}
+`
+	snippetC4Tail = `
+SHOW_LEGEND()
+@enduml
+`
+	snippetC4ElementTag = `AddElementTag("{{shape_style}}", $bgColor="{{background_color_hash}}", $fontColor="{{font_color_hash}}", $borderColor="{{border_color_hash}}"{{c4_shape}})
+`
+	snippetC4RelationTag = `AddRelTag("{{relation_tags}}", $textColor="{{line_color_hash}}", $lineColor="{{line_color_hash}}", $lineStyle=DashedLine(), $legendText="{{c4_legend}}")
+`
+	snippetC4BoundaryHead = `
+Container_Boundary({{container_id}}, "{{title}}") {
+`
+	snippetC4BoundaryTail = `}
+`
+	snippetC4Element = `{{indent}}{{c4_macro}}({{component_id}}, "{{component_name}}"{{component_technology}}, "{{component_desc}}", $tags="{{component_tags}}")
+`
+	snippetC4Relation = `Rel({{component_id_from}}, {{component_id_to}}, "{{relation_label}}", "{{relation_technology}}", $tags="{{relation_tags}}")
+`
+
+	paramC4Include = "{{c4_include}}"
+	paramC4Shape   = "{{c4_shape}}"
+	paramC4Macro   = "{{c4_macro}}"
+	paramC4Legend  = "{{c4_legend}}"
+)
+
+var (
+	c4Shapes = map[string]string{
+		"hexagon": "EightSidedShape()",
+		"rounded": "RoundedBoxShape()",
+	}
+
+	c4MacroSuffixes = map[string]string{
+		"database": "Db",
+		"storage":  "Db",
+		"queue":    "Queue",
+	}
+)
+
+// c4Renderer writes the view using the C4-PlantUML macros.
+//
+// Components are placed in a single container boundary named after the view
+// title, apart from components of `person` and `system` kinds that are
+// rendered outside of it. Component styles are declared as element tags.
+type c4Renderer struct {
+	v         view
+	sb        strings.Builder
+	external  strings.Builder
+	boundary  strings.Builder
+	relations strings.Builder
+}
+
+func newC4Renderer(v view) *c4Renderer {
+	return &c4Renderer{
+		v: v,
+	}
+}
+
+func (r *c4Renderer) head() {
+	include := r.v.c4Include
+	if include == "" {
+		include = defaultC4Include
+	}
+
+	r.sb.WriteString(strings.Replace(snippetC4Head, paramC4Include, include, -1))
+	r.sb.WriteString(buildUMLTitle(r.v.title))
+	r.sb.WriteString("\n")
+
+	for _, s := range r.v.componentStyles {
+		r.sb.WriteString(buildC4ElementTag(s))
+	}
+
+	r.sb.WriteString(buildC4RelationTag(c4RelationTag, r.v.lineColor, "relation"))
+	for _, tag := range r.v.relationDiffTags() {
+		lineColor := r.v.relationLineColor(model.Relation{Tags: []string{tag}})
+		r.sb.WriteString(buildC4RelationTag(tag, lineColor, diffLegend(tag)))
+	}
+}
+
+func (r *c4Renderer) component(c model.Component, shape string, _ string, _ string) {
+	macro, inBoundary := c4Macro(c.Kind, shape)
+	if inBoundary {
+		r.boundary.WriteString(buildC4Element(c, macro, "  "))
+	} else {
+		r.external.WriteString(buildC4Element(c, macro, ""))
+	}
+}
+
+func (r *c4Renderer) relation(srcID string, trgID string, rel model.Relation) {
+	tag := c4RelationTag
+	if diffTag, ok := r.v.diffTag(rel.Tags); ok {
+		tag = diffTag
+	}
+	r.relations.WriteString(buildC4Relation(srcID, trgID, rel, tag))
+}
+
+func (r *c4Renderer) tail() error {
+	if r.external.Len() > 0 {
+		r.sb.WriteString("\n")
+		r.sb.WriteString(r.external.String())
+	}
+
+	if r.boundary.Len() > 0 {
+		s := snippetC4BoundaryHead
+		s = strings.Replace(s, paramContainerID, c4BoundaryID, -1)
+		s = strings.Replace(s, paramTitle, c4Text(r.v.title), -1)
+		r.sb.WriteString(s)
+		r.sb.WriteString(r.boundary.String())
+		r.sb.WriteString(snippetC4BoundaryTail)
+	}
+
+	if r.relations.Len() > 0 {
+		r.sb.WriteString("\n")
+		r.sb.WriteString(r.relations.String())
+	}
+
+	r.sb.WriteString(snippetC4Tail)
+	return nil
+}
+
+func (r *c4Renderer) String() string {
+	return r.sb.String()
+}
+
+// c4Macro returns the C4-PlantUML macro for the component of the given
+// kind and shape, and whether the component belongs to the container.
+func c4Macro(kind string, shape string) (string, bool) {
+	suffix := c4MacroSuffixes[shape]
+
+	switch structurizrElementType(kind) {
+	case structurizrPerson:
+		return "Person", false
+	case structurizrSystem:
+		return "System" + suffix, false
+	case structurizrContainer:
+		return "Container" + suffix, false
+	}
+	return "Component" + suffix, true
+}
+
+func buildC4ElementTag(
+	style ComponentStyle,
+) string {
+	shape := ""
+	if s, ok := c4Shapes[style.shape]; ok {
+		shape = fmt.Sprintf(", $shape=%s", s)
+	}
+
+	s := snippetC4ElementTag
+	s = strings.Replace(s, paramShapeStyle, c4Text(style.id), -1)
+	s = strings.Replace(s, paramBackgroundColor, toHex(style.backgroundColor), -1)
+	s = strings.Replace(s, paramFontColor, toHex(style.fontColor), -1)
+	s = strings.Replace(s, paramBorderColor, toHex(style.borderColor), -1)
+	s = strings.Replace(s, paramC4Shape, shape, -1)
+	return s
+}
+
+func buildC4Element(
+	c model.Component,
+	macro string,
+	indent string,
+) string {
+	// persons and systems have no technology
+	technology := ""
+	if macro != "Person" && !strings.HasPrefix(macro, "System") {
+		technology = fmt.Sprintf(`, "%s"`, c4Text(c.Technology))
+	}
+
+	s := snippetC4Element
+	s = strings.Replace(s, paramIndent, indent, -1)
+	s = strings.Replace(s, paramC4Macro, macro, -1)
+	s = strings.Replace(s, paramComponentID, c4ID(c.ID), -1)
+	s = strings.Replace(s, paramComponentName, c4Text(c.Name), -1)
+	s = strings.Replace(s, paramComponentTechnology, technology, -1)
+	s = strings.Replace(s, paramComponentDescription, c4Text(c.Description), -1)
+	s = strings.Replace(s, paramComponentTags, c4Text(strings.Join(c.Tags, "+")), -1)
+	return s
+}
+
+func buildC4RelationTag(
+	tag string,
+	lineColor color.Color,
+	legend string,
+) string {
+	s := snippetC4RelationTag
+	s = strings.Replace(s, paramRelationTags, tag, -1)
+	s = strings.Replace(s, paramLineColor, toHex(lineColor), -1)
+	s = strings.Replace(s, paramC4Legend, legend, -1)
+	return s
+}
+
+func buildC4Relation(
+	fromID string,
+	toID string,
+	r model.Relation,
+	tag string,
+) string {
+	s := snippetC4Relation
+	s = strings.Replace(s, paramComponentIDFrom, c4ID(fromID), -1)
+	s = strings.Replace(s, paramComponentIDTo, c4ID(toID), -1)
+	s = strings.Replace(s, paramRelationLabel, c4Text(structurizrDescription(r)), -1)
+	s = strings.Replace(s, paramRelationTechnology, c4Text(r.Technology), -1)
+	s = strings.Replace(s, paramRelationTags, tag, -1)
+	return s
+}
+
+// c4ID replaces characters that are not allowed in C4-PlantUML
+// aliases, e.g. dots and slashes in component IDs produced by custom
+// ID strategies or merged structures.
+func c4ID(id string) string {
+	return invalidIDCharsRegexp.ReplaceAllString(id, "_")
+}
+
+// c4Text replaces double quotes, which cannot be escaped
+// in arguments of C4-PlantUML macros.
+func c4Text(s string) string {
+	return strings.Replace(s, `"`, `'`, -1)
+}
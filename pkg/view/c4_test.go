@@ -0,0 +1,179 @@
+package view_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/view"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewView_c4_plantuml_empty(t *testing.T) {
+	s := model.NewStructure()
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatC4PlantUML).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	expectedContent := `This diagram has been generated with go-structurizr 
+[https://github.com/krzysztofreczek/go-structurizr]
+
+@startuml
+!include <C4/C4_Component>
+
+title TITLE UNDEFINED
+
+AddRelTag("go-structurizr", $textColor="#000000", $lineColor="#000000", $lineStyle=DashedLine(), $legendText="relation")
+
+SHOW_LEGEND()
+@enduml
+`
+
+	require.Equal(t, expectedContent, outString)
+}
+
+func TestNewView_c4_plantuml_with_custom_include(t *testing.T) {
+	s := model.NewStructure()
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatC4PlantUML).
+		WithC4Include("./C4_Component.puml").
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "\n!include ./C4_Component.puml\n")
+}
+
+func TestNewView_c4_plantuml_with_components(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:          "ID_1",
+			Kind:        "component",
+			Name:        "app.Handler",
+			Description: `handles "requests"`,
+			Technology:  "Go",
+			Tags:        []string{"ROOT", "HTTP"},
+		},
+		"ID_2": {
+			ID:   "ID_2",
+			Kind: "component",
+			Name: "app.Repository",
+			Tags: []string{"DB"},
+		},
+		"ID_3": {
+			ID:   "ID_3",
+			Kind: "person",
+			Name: "User",
+			Tags: []string{"ROOT"},
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"ID_1": {
+			"ID_2": {
+				Label:      "reads",
+				Technology: "SQL",
+			},
+		},
+		"ID_3": {
+			"ID_1": {
+				Path: "User.handler",
+			},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	style := view.NewComponentStyle("DB").
+		WithBackgroundColor(color.Black).
+		WithFontColor(color.White).
+		WithBorderColor(color.Black).
+		WithShape("database").
+		Build()
+	v := view.NewView().
+		WithFormat(view.FormatC4PlantUML).
+		WithComponentStyle(style).
+		WithRootComponentTag("ROOT").
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `
+AddElementTag("DB", $bgColor="#000000", $fontColor="#ffffff", $borderColor="#000000")
+`)
+	require.Contains(t, outString, `
+Person(ID_3, "User", "", $tags="ROOT")
+`)
+	require.Contains(t, outString, `
+Container_Boundary(container, "TITLE UNDEFINED") {
+`)
+	require.Contains(t, outString, `
+  Component(ID_1, "app.Handler", "Go", "handles 'requests'", $tags="ROOT+HTTP")
+`)
+	require.Contains(t, outString, `
+  ComponentDb(ID_2, "app.Repository", "", "", $tags="DB")
+`)
+	require.Contains(t, outString, `
+Rel(ID_1, ID_2, "reads", "SQL", $tags="go-structurizr")
+`)
+	require.Contains(t, outString, `
+Rel(ID_3, ID_1, "User.handler", "", $tags="go-structurizr")
+`)
+}
+
+func TestNewView_c4_plantuml_with_custom_ids(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"github.com/org/app.Service": {
+			ID:   "github.com/org/app.Service",
+			Kind: "component",
+			Name: "Service",
+		},
+		"container_orders/app.Repository@db-1": {
+			ID:   "container_orders/app.Repository@db-1",
+			Kind: "component",
+			Name: "Repository",
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"github.com/org/app.Service": {
+			"container_orders/app.Repository@db-1": {
+				Label: "reads",
+			},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatC4PlantUML).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `
+  Component(github_com_org_app_Service, "Service", "", "", $tags="")
+`)
+	require.Contains(t, outString, `
+  Component(container_orders_app_Repository_db_1, "Repository", "", "", $tags="")
+`)
+	require.Contains(t, outString, `
+Rel(github_com_org_app_Service, container_orders_app_Repository_db_1, "reads", "", $tags="go-structurizr")
+`)
+	require.NotContains(t, outString, "github.com/org")
+}
@@ -0,0 +1,97 @@
+package view
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+var diffComponentStyles = []ComponentStyle{
+	newComponentStyle(
+		model.DiffTagAdded,
+		color.RGBA{R: 0xc8, G: 0xe6, B: 0xc9, A: 0xff},
+		color.Black,
+		color.RGBA{R: 0x2e, G: 0x7d, B: 0x32, A: 0xff},
+		defaultShape,
+	),
+	newComponentStyle(
+		model.DiffTagRemoved,
+		color.RGBA{R: 0xff, G: 0xcd, B: 0xd2, A: 0xff},
+		color.Black,
+		color.RGBA{R: 0xc6, G: 0x28, B: 0x28, A: 0xff},
+		defaultShape,
+	),
+	newComponentStyle(
+		model.DiffTagChanged,
+		color.RGBA{R: 0xff, G: 0xe0, B: 0xb2, A: 0xff},
+		color.Black,
+		color.RGBA{R: 0xff, G: 0x8f, B: 0x00, A: 0xff},
+		defaultShape,
+	),
+}
+
+func isDiffTag(t string) bool {
+	return t == model.DiffTagAdded ||
+		t == model.DiffTagRemoved ||
+		t == model.DiffTagChanged
+}
+
+// diffTag returns the diff tag the element is marked with,
+// if the view highlights differences.
+func (v view) diffTag(tags []string) (string, bool) {
+	if !v.diffHighlight || len(tags) == 0 || !isDiffTag(tags[0]) {
+		return "", false
+	}
+	return tags[0], true
+}
+
+// componentShape returns the shape of the component style. Highlighted
+// components keep the shape of the style of their original tag.
+func (v view) componentShape(tags []string) string {
+	if len(tags) == 0 {
+		return defaultShape
+	}
+
+	styleTags := tags
+	if _, ok := v.diffTag(tags); ok && len(tags) > 1 {
+		styleTags = tags[1:]
+	}
+
+	s, exists := v.componentStyles[styleTags[0]]
+	if !exists {
+		return defaultShape
+	}
+	return s.shape
+}
+
+// relationDiffTags returns the diff tags of the relations highlighted
+// in the view. Renderers declaring relation styles by tags declare
+// a style for each of them.
+func (v view) relationDiffTags() []string {
+	if !v.diffHighlight {
+		return nil
+	}
+	return []string{model.DiffTagAdded, model.DiffTagRemoved, model.DiffTagChanged}
+}
+
+// diffLegend returns the legend text of relations marked with the diff tag,
+// e.g. `added relation`.
+func diffLegend(tag string) string {
+	return strings.ToLower(tag) + " relation"
+}
+
+// relationLineColor returns the color of the relation line, which is the
+// border color of the diff style for highlighted relations.
+func (v view) relationLineColor(r model.Relation) color.Color {
+	tag, ok := v.diffTag(r.Tags)
+	if !ok {
+		return v.lineColor
+	}
+
+	s, exists := v.componentStyles[tag]
+	if !exists {
+		return v.lineColor
+	}
+	return s.borderColor
+}
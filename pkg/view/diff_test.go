@@ -0,0 +1,173 @@
+package view_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/view"
+	"github.com/stretchr/testify/require"
+)
+
+func diffUnion() model.Structure {
+	old := model.NewStructure()
+	old.AddComponent(model.Component{ID: "ROOT", Name: "root", Tags: []string{"ROOT"}}, "")
+	old.AddComponent(model.Component{ID: "DB", Name: "db", Description: "old", Tags: []string{"DB"}}, "ROOT")
+	old.AddComponent(model.Component{ID: "REMOVED", Name: "removed"}, "ROOT")
+
+	new := model.NewStructure()
+	new.AddComponent(model.Component{ID: "ROOT", Name: "root", Tags: []string{"ROOT"}}, "")
+	new.AddComponent(model.Component{ID: "DB", Name: "db", Description: "new", Tags: []string{"DB"}}, "ROOT")
+	new.AddComponent(model.Component{ID: "ADDED", Name: "added"}, "ROOT")
+
+	return model.Diff(old, new).Union()
+}
+
+func TestNewView_with_diff_highlight(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithComponentStyle(view.NewComponentStyle("DB").WithShape("database").Build()).
+		WithRootComponentTag("ROOT").
+		WithDiffHighlight().
+		Build()
+	err := v.RenderStructureTo(diffUnion(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, "skinparam rectangle<<ADDED>> {\n  BackgroundColor #c8e6c9\n  FontColor #000000\n  BorderColor #2e7d32\n}")
+	require.Contains(t, outString, "skinparam rectangle<<REMOVED>> {\n  BackgroundColor #ffcdd2\n  FontColor #000000\n  BorderColor #c62828\n}")
+	require.Contains(t, outString, "skinparam rectangle<<CHANGED>> {\n  BackgroundColor #ffe0b2\n  FontColor #000000\n  BorderColor #ff8f00\n}")
+	require.Contains(t, outString, `database "==db\n<size:10>[]</size>\n\nnew" <<CHANGED>> as DB`)
+	require.Contains(t, outString, `rectangle "==added\n<size:10>[]</size>\n\n" <<ADDED>> as ADDED`)
+	require.Contains(t, outString, `rectangle "==removed\n<size:10>[]</size>\n\n" <<REMOVED>> as REMOVED`)
+	require.Contains(t, outString, `ROOT .[#000000].> DB : ""`)
+	require.Contains(t, outString, `ROOT .[#2e7d32].> ADDED : ""`)
+	require.Contains(t, outString, `ROOT .[#c62828].> REMOVED : ""`)
+}
+
+func TestNewView_with_diff_highlight_and_custom_style(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithDiffHighlight().
+		WithComponentStyle(view.NewComponentStyle(model.DiffTagAdded).WithBorderColor(color.RGBA{B: 0xff, A: 0xff}).Build()).
+		WithFormat(view.FormatDOT).
+		Build()
+	err := v.RenderStructureTo(diffUnion(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `"ROOT" -> "ADDED" [label="", color="#0000ff", fontcolor="#0000ff"]`)
+	require.Contains(t, outString, `"ROOT" -> "REMOVED" [label="", color="#c62828", fontcolor="#c62828"]`)
+	require.Contains(t, outString, `"ROOT" -> "DB" [label=""]`)
+}
+
+func TestNewView_without_diff_highlight(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatMermaid).
+		Build()
+	err := v.RenderStructureTo(diffUnion(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.NotContains(t, outString, "classDef ADDED")
+	require.NotContains(t, outString, "linkStyle 0")
+}
+
+func TestNewView_mermaid_with_diff_highlight(t *testing.T) {
+	old := model.NewStructure()
+	old.AddComponent(model.Component{ID: "ROOT"}, "")
+
+	new := model.NewStructure()
+	new.AddComponent(model.Component{ID: "ROOT"}, "")
+	new.AddComponent(model.Component{ID: "ADDED"}, "ROOT")
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatMermaid).
+		WithDiffHighlight().
+		Build()
+	err := v.RenderStructureTo(model.Diff(old, new).Union(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, "classDef ADDED fill:#c8e6c9,color:#000000,stroke:#2e7d32")
+	require.Contains(t, outString, ":::ADDED")
+	require.Contains(t, outString, "  linkStyle default stroke:#000000\n  linkStyle 0 stroke:#2e7d32\n")
+}
+
+func TestNewView_c4_with_diff_highlight(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatC4PlantUML).
+		WithDiffHighlight().
+		Build()
+	err := v.RenderStructureTo(diffUnion(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `AddRelTag("ADDED", $textColor="#2e7d32", $lineColor="#2e7d32", $lineStyle=DashedLine(), $legendText="added relation")`)
+	require.Contains(t, outString, `AddRelTag("REMOVED", $textColor="#c62828", $lineColor="#c62828", $lineStyle=DashedLine(), $legendText="removed relation")`)
+	require.Contains(t, outString, `Rel(ROOT, ADDED, "", "", $tags="ADDED")`)
+	require.Contains(t, outString, `Rel(ROOT, REMOVED, "", "", $tags="REMOVED")`)
+	require.Contains(t, outString, `Rel(ROOT, DB, "", "", $tags="go-structurizr")`)
+}
+
+func TestNewView_structurizr_with_diff_highlight(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatStructurizrDSL).
+		WithDiffHighlight().
+		Build()
+	err := v.RenderStructureTo(diffUnion(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `c_ROOT -> c_ADDED "" "" "ADDED"`)
+	require.Contains(t, outString, `            relationship "ADDED" {
+                color #2e7d32
+                dashed true
+            }`)
+	require.Contains(t, outString, `            relationship "REMOVED" {
+                color #c62828
+                dashed true
+            }`)
+}
+
+func TestNewView_structurizr_json_with_diff_highlight(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatStructurizrJSON).
+		WithDiffHighlight().
+		Build()
+	err := v.RenderStructureTo(diffUnion(), &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `"tags": "Relationship,ADDED"`)
+	require.Contains(t, outString, `{
+            "tag": "ADDED",
+            "color": "#2e7d32",
+            "dashed": true
+          }`)
+	require.Contains(t, outString, `{
+            "tag": "REMOVED",
+            "color": "#c62828",
+            "dashed": true
+          }`)
+}
@@ -0,0 +1,181 @@
+package view
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+const (
+	snippetDOTHead = `// This diagram has been generated with go-structurizr
+// [https://github.com/krzysztofreczek/go-structurizr]
+
+digraph "{{title}}" {
+  label="{{title}}"
+  labelloc=t
+  rankdir=TB
+  node [shape=box, style=filled, fillcolor="#ffffff", fontcolor="#000000", color="#000000"]
+  edge [style=dashed, fontsize=10, color="{{line_color_hash}}", fontcolor="{{line_color_hash}}"]
+`
+	snippetDOTTail = `}
+`
+	snippetDOTGroupHead = `
+  subgraph "cluster_{{group_name}}" {
+    label=""
+    style=invis
+`
+	snippetDOTGroupTail = `  }
+`
+	snippetDOTComponent = `    "{{component_id}}" [label="{{component_name}}\n[{{component_kind}}{{component_technology}}]\n\n{{component_desc}}", shape={{shape}}, fillcolor="{{background_color_hash}}", fontcolor="{{font_color_hash}}", color="{{border_color_hash}}"]
+`
+	snippetDOTComponentConnection = `  "{{component_id_from}}" -> "{{component_id_to}}" [label="{{relation_label}}"{{relation_attrs}}]
+`
+	snippetDOTRelationColor = `, color="{{line_color_hash}}", fontcolor="{{line_color_hash}}"`
+
+	paramRelationAttrs = "{{relation_attrs}}"
+)
+
+var (
+	dotShapes = map[string]string{
+		"rectangle":   "box",
+		"component":   "component",
+		"database":    "cylinder",
+		"storage":     "cylinder",
+		"queue":       "cds",
+		"collections": "box3d",
+		"node":        "box3d",
+		"cloud":       "ellipse",
+		"circle":      "circle",
+		"actor":       "ellipse",
+		"person":      "ellipse",
+		"hexagon":     "hexagon",
+		"folder":      "folder",
+		"file":        "note",
+	}
+)
+
+type dotRenderer struct {
+	v         view
+	sb        strings.Builder
+	groups    componentGroups
+	relations strings.Builder
+}
+
+func newDOTRenderer(v view) *dotRenderer {
+	return &dotRenderer{
+		v:      v,
+		groups: newComponentGroups(),
+	}
+}
+
+func (r *dotRenderer) head() {
+	r.sb.WriteString(buildDOTHead(r.v.title, r.v.lineColor))
+}
+
+func (r *dotRenderer) component(c model.Component, shape string, shapeStyle string, group string) {
+	style, ok := r.v.componentStyles[shapeStyle]
+	if !ok {
+		style = newDefaultComponentStyle(shapeStyle)
+	}
+
+	r.groups.add(group, buildDOTComponent(c, shape, style))
+}
+
+func (r *dotRenderer) relation(srcID string, trgID string, rel model.Relation) {
+	attrs := ""
+	if _, ok := r.v.diffTag(rel.Tags); ok {
+		attrs = strings.Replace(snippetDOTRelationColor, paramLineColor, toHex(r.v.relationLineColor(rel)), -1)
+	}
+
+	r.relations.WriteString(buildDOTComponentConnection(srcID, trgID, relationLabel(rel), attrs))
+}
+
+func (r *dotRenderer) tail() error {
+	r.groups.each(func(group string, components string) {
+		r.sb.WriteString(buildDOTGroup(group, components))
+	})
+
+	if r.relations.Len() > 0 {
+		r.sb.WriteString("\n")
+		r.sb.WriteString(r.relations.String())
+	}
+
+	r.sb.WriteString(snippetDOTTail)
+	return nil
+}
+
+func (r *dotRenderer) String() string {
+	return r.sb.String()
+}
+
+func buildDOTHead(
+	title string,
+	lineColor color.Color,
+) string {
+	s := snippetDOTHead
+	s = strings.Replace(s, paramTitle, dotText(title), -1)
+	s = strings.Replace(s, paramLineColor, toHex(lineColor), -1)
+	return s
+}
+
+func buildDOTGroup(
+	group string,
+	body string,
+) string {
+	s := strings.Replace(snippetDOTGroupHead, paramGroupName, dotText(group), -1)
+	s += body
+	s += snippetDOTGroupTail
+	return s
+}
+
+func buildDOTComponent(
+	c model.Component,
+	shape string,
+	style ComponentStyle,
+) string {
+	dotShape, ok := dotShapes[shape]
+	if !ok {
+		dotShape = dotShapes[defaultShape]
+	}
+
+	s := snippetDOTComponent
+	s = strings.Replace(s, paramShape, dotShape, -1)
+	s = strings.Replace(s, paramBackgroundColor, toHex(style.backgroundColor), -1)
+	s = strings.Replace(s, paramFontColor, toHex(style.fontColor), -1)
+	s = strings.Replace(s, paramBorderColor, toHex(style.borderColor), -1)
+	s = strings.Replace(s, paramComponentID, dotText(c.ID), -1)
+	s = strings.Replace(s, paramComponentName, dotText(c.Name), -1)
+	s = strings.Replace(s, paramComponentKind, dotText(c.Kind), -1)
+	s = strings.Replace(s, paramComponentDescription, dotText(c.Description), -1)
+
+	technology := c.Technology
+	if technology != "" {
+		technology = ":" + technology
+	}
+	s = strings.Replace(s, paramComponentTechnology, dotText(technology), -1)
+
+	return s
+}
+
+func buildDOTComponentConnection(
+	fromID string,
+	toID string,
+	label string,
+	attrs string,
+) string {
+	s := snippetDOTComponentConnection
+	s = strings.Replace(s, paramComponentIDFrom, dotText(fromID), -1)
+	s = strings.Replace(s, paramComponentIDTo, dotText(toID), -1)
+	// line breaks of the label are already escaped the way DOT expects
+	s = strings.Replace(s, paramRelationLabel, strings.Replace(label, `"`, `\"`, -1), -1)
+	s = strings.Replace(s, paramRelationAttrs, attrs, -1)
+	return s
+}
+
+// dotText escapes the text to be used within a quoted DOT string.
+func dotText(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return s
+}
@@ -0,0 +1,135 @@
+package view_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/view"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewView_dot_empty(t *testing.T) {
+	s := model.NewStructure()
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatDOT).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	expectedContent := `// This diagram has been generated with go-structurizr
+// [https://github.com/krzysztofreczek/go-structurizr]
+
+digraph "TITLE UNDEFINED" {
+  label="TITLE UNDEFINED"
+  labelloc=t
+  rankdir=TB
+  node [shape=box, style=filled, fillcolor="#ffffff", fontcolor="#000000", color="#000000"]
+  edge [style=dashed, fontsize=10, color="#000000", fontcolor="#000000"]
+}
+`
+
+	require.Equal(t, expectedContent, outString)
+}
+
+func TestNewView_dot_with_component_of_custom_style(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:          "ID_1",
+			Kind:        "component",
+			Name:        "test.Component",
+			Description: `"quoted" description`,
+			Technology:  "technology",
+			Tags:        []string{"DB"},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	style := view.NewComponentStyle("DB").
+		WithBackgroundColor(color.Black).
+		WithFontColor(color.White).
+		WithBorderColor(color.White).
+		WithShape("database").
+		Build()
+	v := view.NewView().
+		WithFormat(view.FormatDOT).
+		WithComponentStyle(style).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	expectedContent := `
+  subgraph "cluster_0DB" {
+    label=""
+    style=invis
+    "ID_1" [label="test.Component\n[component:technology]\n\n\"quoted\" description", shape=cylinder, fillcolor="#000000", fontcolor="#ffffff", color="#ffffff"]
+  }
+`
+	require.Contains(t, outString, expectedContent)
+}
+
+func TestNewView_dot_with_relations(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:   "ID_1",
+			Tags: []string{"ROOT"},
+		},
+		"ID_2": {
+			ID:   "ID_2",
+			Tags: []string{"TAG"},
+		},
+		"ID_3": {
+			ID:   "ID_3",
+			Tags: []string{"TAG"},
+		},
+		"ID_4": {
+			ID:   "ID_4",
+			Tags: []string{"OTHER"},
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"ID_1": {
+			"ID_2": {},
+			"ID_3": {
+				Label:      "reads",
+				Technology: "SQL",
+			},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatDOT).
+		WithRootComponentTag("ROOT").
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `
+  subgraph "cluster_ID_11TAG" {
+`)
+	require.Contains(t, outString, `
+    "ID_2" [label="\n[]\n\n", shape=box, fillcolor="#ffffff", fontcolor="#000000", color="#000000"]
+`)
+	require.Contains(t, outString, `
+  "ID_1" -> "ID_2" [label=""]
+`)
+	require.Contains(t, outString, `
+  "ID_1" -> "ID_3" [label="reads\n[SQL]"]
+`)
+	require.NotContains(t, outString, `ID_4`)
+}
@@ -1,23 +1,47 @@
 package view
 
 import (
+	gocontext "context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
 )
 
-func (v view) isDebugMode() bool {
+// newLogger returns the logger debug messages are written to.
+// If no logger is provided, messages are written with the standard logger
+// only if the `LOG_LEVEL` environment variable is set to `DEBUG`.
+func newLogger(l *slog.Logger) *slog.Logger {
+	if l != nil {
+		return l
+	}
+	if !isDebugEnv() {
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(log.Writer(), &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+}
+
+func isDebugEnv() bool {
 	level := os.Getenv("LOG_LEVEL")
 	return level == "DEBUG" || level == "debug"
 }
 
+func (v view) isDebugMode() bool {
+	return v.logger != nil && v.logger.Enabled(gocontext.Background(), slog.LevelDebug)
+}
+
 func (v view) debug(c model.Component, format string, a ...interface{}) {
 	if !v.isDebugMode() {
 		return
 	}
 
-	m := fmt.Sprintf(format, a...)
-	log.Printf("[%s][id: %s] %s\n", c.Name, c.ID, m)
+	v.logger.LogAttrs(gocontext.Background(), slog.LevelDebug, fmt.Sprintf(format, a...),
+		slog.String("id", c.ID),
+		slog.String("name", c.Name),
+		slog.Any("tags", c.Tags),
+	)
 }
@@ -0,0 +1,226 @@
+package view
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+)
+
+const (
+	snippetMermaidHead = `---
+title: {{title}}
+---
+%% This diagram has been generated with go-structurizr
+%% [https://github.com/krzysztofreczek/go-structurizr]
+
+flowchart TB
+`
+	snippetMermaidClassDef = `  classDef {{shape_style}} fill:{{background_color_hash}},color:{{font_color_hash}},stroke:{{border_color_hash}}
+`
+	snippetMermaidGroupHead = `
+  subgraph {{group_name}} [" "]
+`
+	snippetMermaidGroupTail = `  end
+  style {{group_name}} fill:none,stroke:none
+`
+	snippetMermaidComponent = `    {{component_id}}{{shape_open}}"<b>{{component_name}}</b><br/><small>[{{component_kind}}{{component_technology}}]</small><br/><br/>{{component_desc}}"{{shape_close}}{{component_class}}
+`
+	snippetMermaidComponentConnection = `  {{component_id_from}} -.->{{relation_label}} {{component_id_to}}
+`
+	snippetMermaidLinkStyle = `  linkStyle default stroke:{{line_color_hash}}
+`
+	snippetMermaidIndexedLinkStyle = `  linkStyle {{link_index}} stroke:{{line_color_hash}}
+`
+
+	paramLinkIndex = "{{link_index}}"
+
+	paramShapeOpen      = "{{shape_open}}"
+	paramShapeClose     = "{{shape_close}}"
+	paramComponentClass = "{{component_class}}"
+)
+
+var (
+	mermaidShapes = map[string][2]string{
+		"rectangle":   {"[", "]"},
+		"component":   {"[[", "]]"},
+		"database":    {"[(", ")]"},
+		"storage":     {"[(", ")]"},
+		"queue":       {"[/", "/]"},
+		"collections": {"[[", "]]"},
+		"cloud":       {"((", "))"},
+		"circle":      {"((", "))"},
+		"actor":       {"([", "])"},
+		"person":      {"([", "])"},
+		"hexagon":     {"{{", "}}"},
+	}
+)
+
+type mermaidRenderer struct {
+	v          view
+	sb         strings.Builder
+	groups     componentGroups
+	relations  strings.Builder
+	linkStyles strings.Builder
+	links      int
+}
+
+func newMermaidRenderer(v view) *mermaidRenderer {
+	return &mermaidRenderer{
+		v:      v,
+		groups: newComponentGroups(),
+	}
+}
+
+func (r *mermaidRenderer) head() {
+	r.sb.WriteString(buildMermaidHead(r.v.title))
+
+	for _, s := range r.v.componentStyles {
+		r.sb.WriteString(buildMermaidClassDef(s))
+	}
+}
+
+// component buffers the component in its group, as Mermaid subgraphs
+// must be declared at once.
+func (r *mermaidRenderer) component(c model.Component, shape string, shapeStyle string, group string) {
+	class := ""
+	if _, ok := r.v.componentStyles[shapeStyle]; ok {
+		class = ":::" + mermaidID(shapeStyle)
+	}
+
+	r.groups.add(mermaidID("group_"+group), buildMermaidComponent(c, shape, class))
+}
+
+func (r *mermaidRenderer) relation(srcID string, trgID string, rel model.Relation) {
+	if _, ok := r.v.diffTag(rel.Tags); ok {
+		r.linkStyles.WriteString(buildMermaidIndexedLinkStyle(r.links, r.v.relationLineColor(rel)))
+	}
+
+	r.relations.WriteString(buildMermaidComponentConnection(srcID, trgID, relationLabel(rel)))
+	r.links++
+}
+
+func (r *mermaidRenderer) tail() error {
+	r.groups.each(func(group string, components string) {
+		r.sb.WriteString(buildMermaidGroup(group, components))
+	})
+
+	if r.relations.Len() > 0 {
+		r.sb.WriteString("\n")
+		r.sb.WriteString(r.relations.String())
+		r.sb.WriteString(buildMermaidLinkStyle(r.v.lineColor))
+		r.sb.WriteString(r.linkStyles.String())
+	}
+	return nil
+}
+
+func (r *mermaidRenderer) String() string {
+	return r.sb.String()
+}
+
+func buildMermaidHead(
+	title string,
+) string {
+	s := snippetMermaidHead
+	s = strings.Replace(s, paramTitle, title, -1)
+	return s
+}
+
+func buildMermaidClassDef(
+	style ComponentStyle,
+) string {
+	s := snippetMermaidClassDef
+	s = strings.Replace(s, paramShapeStyle, mermaidID(style.id), -1)
+	s = strings.Replace(s, paramBackgroundColor, toHex(style.backgroundColor), -1)
+	s = strings.Replace(s, paramFontColor, toHex(style.fontColor), -1)
+	s = strings.Replace(s, paramBorderColor, toHex(style.borderColor), -1)
+	return s
+}
+
+func buildMermaidGroup(
+	group string,
+	body string,
+) string {
+	s := strings.Replace(snippetMermaidGroupHead, paramGroupName, group, -1)
+	s += body
+	s += strings.Replace(snippetMermaidGroupTail, paramGroupName, group, -1)
+	return s
+}
+
+func buildMermaidComponent(
+	c model.Component,
+	shape string,
+	class string,
+) string {
+	brackets, ok := mermaidShapes[shape]
+	if !ok {
+		brackets = mermaidShapes[defaultShape]
+	}
+
+	s := snippetMermaidComponent
+	s = strings.Replace(s, paramShapeOpen, brackets[0], -1)
+	s = strings.Replace(s, paramShapeClose, brackets[1], -1)
+	s = strings.Replace(s, paramComponentClass, class, -1)
+	s = strings.Replace(s, paramComponentID, mermaidID(c.ID), -1)
+	s = strings.Replace(s, paramComponentName, mermaidText(c.Name), -1)
+	s = strings.Replace(s, paramComponentKind, mermaidText(c.Kind), -1)
+	s = strings.Replace(s, paramComponentDescription, mermaidText(c.Description), -1)
+
+	technology := c.Technology
+	if technology != "" {
+		technology = ":" + technology
+	}
+	s = strings.Replace(s, paramComponentTechnology, mermaidText(technology), -1)
+
+	return s
+}
+
+func buildMermaidComponentConnection(
+	fromID string,
+	toID string,
+	label string,
+) string {
+	if label != "" {
+		label = `|"` + mermaidText(label) + `"|`
+	}
+
+	s := snippetMermaidComponentConnection
+	s = strings.Replace(s, paramComponentIDFrom, mermaidID(fromID), -1)
+	s = strings.Replace(s, paramComponentIDTo, mermaidID(toID), -1)
+	s = strings.Replace(s, paramRelationLabel, label, -1)
+	return s
+}
+
+func buildMermaidLinkStyle(
+	lineColor color.Color,
+) string {
+	s := snippetMermaidLinkStyle
+	s = strings.Replace(s, paramLineColor, toHex(lineColor), -1)
+	return s
+}
+
+func buildMermaidIndexedLinkStyle(
+	index int,
+	lineColor color.Color,
+) string {
+	s := snippetMermaidIndexedLinkStyle
+	s = strings.Replace(s, paramLinkIndex, strconv.Itoa(index), -1)
+	s = strings.Replace(s, paramLineColor, toHex(lineColor), -1)
+	return s
+}
+
+// mermaidID replaces characters that are not allowed in Mermaid
+// identifiers, e.g. spaces in tags used as class names or dots and slashes
+// in component IDs produced by custom ID strategies.
+func mermaidID(id string) string {
+	return invalidIDCharsRegexp.ReplaceAllString(id, "_")
+}
+
+// mermaidText escapes quotes and translates PlantUML line breaks
+// used in labels.
+func mermaidText(s string) string {
+	s = strings.Replace(s, `"`, "#quot;", -1)
+	s = strings.Replace(s, `\n`, "<br/>", -1)
+	return s
+}
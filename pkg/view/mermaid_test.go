@@ -0,0 +1,190 @@
+package view_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/view"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewView_mermaid_empty(t *testing.T) {
+	s := model.NewStructure()
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatMermaid).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	expectedContent := `---
+title: TITLE UNDEFINED
+---
+%% This diagram has been generated with go-structurizr
+%% [https://github.com/krzysztofreczek/go-structurizr]
+
+flowchart TB
+`
+
+	require.Equal(t, expectedContent, outString)
+}
+
+func TestNewView_mermaid_with_component_of_custom_style(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:          "ID_1",
+			Kind:        "component",
+			Name:        "test.Component",
+			Description: `"quoted" description`,
+			Technology:  "technology",
+			Tags:        []string{"DB STYLE"},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	style := view.NewComponentStyle("DB STYLE").
+		WithBackgroundColor(color.White).
+		WithFontColor(color.Black).
+		WithBorderColor(color.White).
+		WithShape("database").
+		Build()
+	v := view.NewView().
+		WithFormat(view.FormatMermaid).
+		WithComponentStyle(style).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	expectedContent := `
+  classDef DB_STYLE fill:#ffffff,color:#000000,stroke:#ffffff
+`
+	require.Contains(t, outString, expectedContent)
+
+	expectedContent = `
+  subgraph group_0DB_STYLE [" "]
+    ID_1[("<b>test.Component</b><br/><small>[component:technology]</small><br/><br/>#quot;quoted#quot; description")]:::DB_STYLE
+  end
+  style group_0DB_STYLE fill:none,stroke:none
+`
+	require.Contains(t, outString, expectedContent)
+}
+
+func TestNewView_mermaid_with_relations(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:   "ID_1",
+			Tags: []string{"ROOT"},
+		},
+		"ID_2": {
+			ID:   "ID_2",
+			Tags: []string{"TAG"},
+		},
+		"ID_3": {
+			ID:   "ID_3",
+			Tags: []string{"TAG"},
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"ID_1": {
+			"ID_2": {},
+			"ID_3": {
+				Label:      "reads",
+				Technology: "SQL",
+			},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatMermaid).
+		WithRootComponentTag("ROOT").
+		WithLineColor(color.White).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `
+  subgraph group_ID_11TAG [" "]
+`)
+	require.Contains(t, outString, `
+    ID_2["<b></b><br/><small>[]</small><br/><br/>"]
+`)
+	require.Contains(t, outString, `
+    ID_3["<b></b><br/><small>[]</small><br/><br/>"]
+`)
+	require.Contains(t, outString, `
+  ID_1 -.-> ID_2
+`)
+	require.Contains(t, outString, `
+  ID_1 -.->|"reads<br/>[SQL]"| ID_3
+`)
+	require.Contains(t, outString, `
+  linkStyle default stroke:#ffffff
+`)
+}
+
+func TestNewView_mermaid_with_custom_ids(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"github.com/org/app.Service": {
+			ID:   "github.com/org/app.Service",
+			Name: "Service",
+		},
+		"github.com/org/app.Orders DB": {
+			ID:   "github.com/org/app.Orders DB",
+			Name: "Orders DB",
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"github.com/org/app.Service": {
+			"github.com/org/app.Orders DB": {},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatMermaid).
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	require.Contains(t, outString, `
+    github_com_org_app_Service["<b>Service</b><br/><small>[]</small><br/><br/>"]
+`)
+	require.Contains(t, outString, `
+    github_com_org_app_Orders_DB["<b>Orders DB</b><br/><small>[]</small><br/><br/>"]
+`)
+	require.Contains(t, outString, `
+  github_com_org_app_Service -.-> github_com_org_app_Orders_DB
+`)
+	require.NotContains(t, outString, "github.com/org")
+}
+
+func TestNewView_with_unknown_format(t *testing.T) {
+	s := model.NewStructure()
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat("unknown").
+		Build()
+	err := v.RenderStructureTo(s, &out)
+	require.Error(t, err)
+}
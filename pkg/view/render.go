@@ -15,33 +15,34 @@ const (
 
 // RenderStructureTo renders the provided `model.Structure` to any `io.Writer`.
 //
-// It returns an error if the writer cannot be used.
+// It returns an error if the writer cannot be used, the format
+// of the view is not supported or the view cannot be rendered.
 func (v view) RenderStructureTo(s model.Structure, w io.Writer) error {
-	out := v.render(s)
-	_, err := w.Write([]byte(out))
+	out, err := v.render(s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(out))
 	return err
 }
 
-func (v view) render(s model.Structure) string {
-	sb := strings.Builder{}
-
-	sb.WriteString(buildUMLHead())
-	sb.WriteString(buildUMLTitle(v.title))
-	sb.WriteString(buildSkinParamDefault())
-	sb.WriteString(buildSkinParamGroup())
-
-	for _, s := range v.componentStyles {
-		sb.WriteString(buildSkinParamShape(s.id, s.backgroundColor, s.fontColor, s.borderColor, s.shape))
+func (v view) render(s model.Structure) (string, error) {
+	r, err := v.newRenderer()
+	if err != nil {
+		return "", err
 	}
 
-	sb.WriteString(v.renderBody(s))
-	sb.WriteString(buildUMLTail())
+	r.head()
+	v.renderBody(s, r)
+	if err := r.tail(); err != nil {
+		return "", err
+	}
 
-	return sb.String()
+	return r.String(), nil
 }
 
-func (v view) renderBody(s model.Structure) string {
-	ctx := v.newContext(s)
+func (v view) renderBody(s model.Structure, r renderer) {
+	ctx := v.newContext(s, r)
 
 	v.renderRootComponents(ctx)
 
@@ -52,12 +53,10 @@ func (v view) renderBody(s model.Structure) string {
 			break
 		}
 	}
-
-	return ctx.sb.String()
 }
 
 type context struct {
-	sb                strings.Builder
+	r                 renderer
 	s                 model.Structure
 	excludedIDs       map[string]struct{}
 	renderedIDs       map[string]struct{}
@@ -65,9 +64,9 @@ type context struct {
 	level             int
 }
 
-func (v view) newContext(s model.Structure) *context {
+func (v view) newContext(s model.Structure, r renderer) *context {
 	return &context{
-		sb:                strings.Builder{},
+		r:                 r,
 		s:                 s,
 		excludedIDs:       v.resolveExcludedComponentIDs(s),
 		renderedIDs:       map[string]struct{}{},
@@ -105,14 +104,14 @@ func (v view) renderNextBodyLayer(ctx *context) int {
 	for srcID := range renderedPreviously {
 		srcRelations := ctx.s.Relations[srcID]
 
-		for trgID := range srcRelations {
+		for trgID, r := range srcRelations {
 			c, exists := ctx.s.Components[trgID]
 			if !exists {
 				continue
 			}
 
 			v.renderComponent(ctx, c, srcID)
-			v.renderRelation(ctx, srcID, trgID)
+			v.renderRelation(ctx, srcID, trgID, r)
 		}
 	}
 
@@ -131,25 +130,21 @@ func (v view) renderComponent(ctx *context, c model.Component, parentID string)
 		return
 	}
 
-	shape := defaultShape
+	shape := v.componentShape(c.Tags)
 	shapeStyle := defaultShapeStyle
 	if len(c.Tags) > 0 {
 		shapeStyle = c.Tags[0]
-		s, exists := v.componentStyles[shapeStyle]
-		if exists {
-			shape = s.shape
-		}
 	}
 
 	group := groupID(parentID, shapeStyle, ctx.level)
 
 	v.debug(c, "rendering component with shape '%s', shape style '%s', and group '%s'", shape, shapeStyle, group)
 
-	ctx.sb.WriteString(buildComponent(c, shape, shapeStyle, group))
+	ctx.r.component(c, shape, shapeStyle, group)
 	ctx.renderedIDs[c.ID] = struct{}{}
 }
 
-func (v view) renderRelation(ctx *context, srcID string, trgID string) {
+func (v view) renderRelation(ctx *context, srcID string, trgID string, r model.Relation) {
 	_, rendered := ctx.renderedIDs[trgID]
 	if !rendered {
 		return
@@ -162,7 +157,7 @@ func (v view) renderRelation(ctx *context, srcID string, trgID string) {
 
 	v.debug(ctx.s.Components[srcID], "rendering relation to component of id '%s'", trgID)
 
-	ctx.sb.WriteString(buildComponentConnection(srcID, trgID, v.lineColor))
+	ctx.r.relation(srcID, trgID, r)
 	ctx.renderedRelations[relationID] = struct{}{}
 }
 
@@ -202,6 +197,21 @@ func groupID(parentID string, style string, level int) string {
 	return strings.Join([]string{parentID, strconv.Itoa(level), style}, "")
 }
 
+// relationLabel returns the label of the relation or, if not provided,
+// the path of fields and methods leading from the source to the target.
+func relationLabel(r model.Relation) string {
+	label := r.Label
+	if label == "" {
+		label = r.Path
+	}
+
+	if r.Technology != "" {
+		label = strings.TrimPrefix(label+`\n[`+r.Technology+`]`, `\n`)
+	}
+
+	return label
+}
+
 func relationID(srcID string, trgID string) string {
 	return strings.Join([]string{srcID, trgID}, "")
 }
@@ -0,0 +1,135 @@
+package view
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// Format defines the language the view is rendered in.
+type Format string
+
+const (
+	// FormatPlantUML renders the view as a PlantUML diagram. It is the default format.
+	FormatPlantUML Format = "plantuml"
+	// FormatMermaid renders the view as a Mermaid flowchart.
+	FormatMermaid Format = "mermaid"
+	// FormatDOT renders the view as a Graphviz DOT graph.
+	FormatDOT Format = "dot"
+	// FormatStructurizrDSL renders the view as a Structurizr DSL workspace.
+	FormatStructurizrDSL Format = "structurizr-dsl"
+	// FormatStructurizrJSON renders the view as a Structurizr JSON workspace.
+	FormatStructurizrJSON Format = "structurizr-json"
+	// FormatC4PlantUML renders the view as a PlantUML diagram using
+	// the C4-PlantUML standard library macros.
+	FormatC4PlantUML Format = "c4-plantuml"
+)
+
+var invalidIDCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// renderer writes the view in a specific format.
+//
+// Components and relations are passed in the order they are reached
+// while traversing the structure from the root components.
+// A relation is passed only once both its components have been passed.
+// The tail returns an error if the view cannot be rendered.
+type renderer interface {
+	head()
+	component(c model.Component, shape string, shapeStyle string, group string)
+	relation(srcID string, trgID string, r model.Relation)
+	tail() error
+	String() string
+}
+
+var renderers = map[Format]func(v view) renderer{
+	FormatPlantUML: func(v view) renderer { return newPlantUMLRenderer(v) },
+	FormatMermaid:  func(v view) renderer { return newMermaidRenderer(v) },
+	FormatDOT:      func(v view) renderer { return newDOTRenderer(v) },
+	FormatStructurizrDSL: func(v view) renderer {
+		return newStructurizrRenderer(v, false)
+	},
+	FormatStructurizrJSON: func(v view) renderer {
+		return newStructurizrRenderer(v, true)
+	},
+	FormatC4PlantUML: func(v view) renderer { return newC4Renderer(v) },
+}
+
+func (v view) newRenderer() (renderer, error) {
+	newRenderer, ok := renderers[v.format]
+	if !ok {
+		return nil, errors.Errorf("unknown view format `%s`", v.format)
+	}
+	return newRenderer(v), nil
+}
+
+type plantUMLRenderer struct {
+	v  view
+	sb strings.Builder
+}
+
+func newPlantUMLRenderer(v view) *plantUMLRenderer {
+	return &plantUMLRenderer{
+		v: v,
+	}
+}
+
+func (r *plantUMLRenderer) head() {
+	r.sb.WriteString(buildUMLHead())
+	r.sb.WriteString(buildUMLTitle(r.v.title))
+	r.sb.WriteString(buildSkinParamDefault())
+	r.sb.WriteString(buildSkinParamGroup())
+
+	for _, s := range r.v.componentStyles {
+		r.sb.WriteString(buildSkinParamShape(s.id, s.backgroundColor, s.fontColor, s.borderColor, s.shape))
+	}
+}
+
+func (r *plantUMLRenderer) component(c model.Component, shape string, shapeStyle string, group string) {
+	r.sb.WriteString(buildComponent(c, shape, shapeStyle, group))
+}
+
+func (r *plantUMLRenderer) relation(srcID string, trgID string, rel model.Relation) {
+	r.sb.WriteString(buildComponentConnection(srcID, trgID, relationLabel(rel), r.v.relationLineColor(rel)))
+}
+
+func (r *plantUMLRenderer) tail() error {
+	r.sb.WriteString(buildUMLTail())
+	return nil
+}
+
+func (r *plantUMLRenderer) String() string {
+	return r.sb.String()
+}
+
+// componentGroups buffers rendered components by their groups
+// for formats that require each group to be declared at once.
+type componentGroups struct {
+	groups map[string]*strings.Builder
+	order  []string
+}
+
+func newComponentGroups() componentGroups {
+	return componentGroups{
+		groups: make(map[string]*strings.Builder),
+	}
+}
+
+func (g *componentGroups) add(group string, component string) {
+	sb, ok := g.groups[group]
+	if !ok {
+		sb = &strings.Builder{}
+		g.groups[group] = sb
+		g.order = append(g.order, group)
+	}
+	sb.WriteString(component)
+}
+
+// each calls the function for each group in the order the groups
+// have been reached.
+func (g *componentGroups) each(f func(group string, components string)) {
+	for _, group := range g.order {
+		f(group, g.groups[group].String())
+	}
+}
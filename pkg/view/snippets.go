@@ -51,7 +51,7 @@ rectangle {{group_name}} <<_GROUP>> {
 	{{shape}} "=={{component_name}}\n<size:10>[{{component_kind}}{{component_technology}}]</size>\n\n{{component_desc}}" <<{{shape_style}}>> as {{component_id}}
 }`
 	snippetComponentConnection = `
-{{component_id_from}} .[{{line_color_hash}}].> {{component_id_to}} : ""`
+{{component_id_from}} .[{{line_color_hash}}].> {{component_id_to}} : "{{relation_label}}"`
 
 	paramComponentID          = "{{component_id}}"
 	paramComponentIDFrom      = "{{component_id_from}}"
@@ -60,6 +60,7 @@ rectangle {{group_name}} <<_GROUP>> {
 	paramComponentKind        = "{{component_kind}}"
 	paramComponentTechnology  = "{{component_technology}}"
 	paramComponentDescription = "{{component_desc}}"
+	paramRelationLabel        = "{{relation_label}}"
 	paramTitle                = "{{title}}"
 	paramGroupName            = "{{group_name}}"
 	paramBackgroundColor      = "{{background_color_hash}}"
@@ -137,15 +138,23 @@ func buildComponent(
 func buildComponentConnection(
 	fromID string,
 	toID string,
+	label string,
 	lineColor color.Color,
 ) string {
 	s := snippetComponentConnection
 	s = strings.Replace(s, paramComponentIDFrom, fromID, -1)
 	s = strings.Replace(s, paramComponentIDTo, toID, -1)
+	s = strings.Replace(s, paramRelationLabel, plantUMLText(label), -1)
 	s = strings.Replace(s, paramLineColor, toHex(lineColor), -1)
 	return s
 }
 
+// plantUMLText replaces double quotes, which would terminate
+// the quoted labels of PlantUML relations.
+func plantUMLText(s string) string {
+	return strings.Replace(s, `"`, `'`, -1)
+}
+
 func toHex(c color.Color) string {
 	rgba := color.RGBAModel.Convert(c).(color.RGBA)
 	return fmt.Sprintf("#%.2x%.2x%.2x", rgba.R, rgba.G, rgba.B)
@@ -0,0 +1,649 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	structurizrSystemID    = "system"
+	structurizrContainerID = "container"
+	structurizrDefaultView = "components"
+
+	structurizrPerson    = "person"
+	structurizrSystem    = "softwareSystem"
+	structurizrContainer = "container"
+	structurizrComponent = "component"
+
+	snippetStructurizrHead = `/*
+ * This workspace has been generated with go-structurizr
+ * [https://github.com/krzysztofreczek/go-structurizr]
+ */
+workspace "{{title}}" {
+
+    model {
+`
+	snippetStructurizrModelTail = `    }
+
+    views {
+`
+	snippetStructurizrTail = `    }
+}
+`
+	snippetStructurizrSystemHead = `        {{system_id}} = softwareSystem "{{title}}" {
+`
+	snippetStructurizrSystemTail = `        }
+`
+	snippetStructurizrContainerHead = `            {{container_id}} = container "{{title}}" {
+`
+	snippetStructurizrContainerTail = `            }
+`
+	snippetStructurizrElement = `{{indent}}{{component_id}} = {{element_type}} "{{component_name}}" "{{component_desc}}"{{component_technology}} "{{component_tags}}"
+`
+	snippetStructurizrRelationship = `        {{component_id_from}} -> {{component_id_to}} "{{relation_label}}" "{{relation_technology}}" "{{relation_tags}}"
+`
+	snippetStructurizrView = `        component {{container_id}} "{{view_key}}" "{{title}}" {
+            include {{view_elements}}
+            autoLayout
+        }
+
+`
+	snippetStructurizrStylesHead = `        styles {
+`
+	snippetStructurizrStylesTail = `        }
+`
+	snippetStructurizrElementStyle = `            element "{{shape_style}}" {
+                background {{background_color_hash}}
+                color {{font_color_hash}}
+                stroke {{border_color_hash}}
+                shape {{shape}}
+            }
+`
+	snippetStructurizrRelationshipStyle = `            relationship "{{relation_tags}}" {
+                color {{line_color_hash}}
+                dashed true
+            }
+`
+
+	paramSystemID           = "{{system_id}}"
+	paramContainerID        = "{{container_id}}"
+	paramIndent             = "{{indent}}"
+	paramElementType        = "{{element_type}}"
+	paramComponentTags      = "{{component_tags}}"
+	paramRelationTechnology = "{{relation_technology}}"
+	paramRelationTags       = "{{relation_tags}}"
+	paramViewKey            = "{{view_key}}"
+	paramViewElements       = "{{view_elements}}"
+)
+
+var (
+	structurizrShapes = map[string]string{
+		"rectangle":   "Box",
+		"component":   "Component",
+		"database":    "Cylinder",
+		"storage":     "Cylinder",
+		"queue":       "Pipe",
+		"collections": "Box",
+		"node":        "Box",
+		"cloud":       "Ellipse",
+		"circle":      "Circle",
+		"actor":       "Person",
+		"person":      "Person",
+		"hexagon":     "Hexagon",
+		"folder":      "Folder",
+	}
+
+	structurizrTags = map[string]string{
+		structurizrPerson:    "Element,Person",
+		structurizrSystem:    "Element,Software System",
+		structurizrContainer: "Element,Container",
+		structurizrComponent: "Element,Component",
+	}
+)
+
+// structurizrRenderer collects the components and relations of the view
+// and writes them as a Structurizr workspace.
+//
+// Scraped components are placed in a single container of a single software
+// system, both named after the view title. Components of `person`,
+// `softwareSystem` and `container` kinds become elements of these types.
+// A component view is created for each root component tag.
+type structurizrRenderer struct {
+	v          view
+	asJSON     bool
+	components []model.Component
+	relations  []model.Relation
+	out        string
+}
+
+func newStructurizrRenderer(v view, asJSON bool) *structurizrRenderer {
+	return &structurizrRenderer{
+		v:      v,
+		asJSON: asJSON,
+	}
+}
+
+func (r *structurizrRenderer) head() {}
+
+func (r *structurizrRenderer) component(c model.Component, _ string, _ string, _ string) {
+	r.components = append(r.components, c)
+}
+
+func (r *structurizrRenderer) relation(srcID string, trgID string, rel model.Relation) {
+	rel.SourceID = srcID
+	rel.TargetID = trgID
+	r.relations = append(r.relations, rel)
+}
+
+func (r *structurizrRenderer) tail() error {
+	sort.Slice(r.components, func(i, j int) bool {
+		return r.components[i].ID < r.components[j].ID
+	})
+	sort.Slice(r.relations, func(i, j int) bool {
+		return structurizrRelationshipID(r.relations[i]) < structurizrRelationshipID(r.relations[j])
+	})
+
+	if !r.asJSON {
+		r.out = r.renderDSL()
+		return nil
+	}
+
+	out, err := r.renderJSON()
+	if err != nil {
+		return err
+	}
+	r.out = out
+	return nil
+}
+
+func (r *structurizrRenderer) String() string {
+	return r.out
+}
+
+type structurizrView struct {
+	key        string
+	title      string
+	elementIDs []string
+}
+
+// views returns a component view for each root component tag including
+// all the components reachable from the components of the tag.
+// If there are no root component tags, a single view including all the
+// components is returned.
+func (r *structurizrRenderer) views() []structurizrView {
+	if len(r.components) == 0 {
+		return nil
+	}
+
+	if len(r.v.rootComponentTags) == 0 {
+		ids := make([]string, len(r.components))
+		for i, c := range r.components {
+			ids[i] = c.ID
+		}
+		return []structurizrView{{key: structurizrDefaultView, title: r.v.title, elementIDs: ids}}
+	}
+
+	relations := make(map[string][]string)
+	for _, rel := range r.relations {
+		relations[rel.SourceID] = append(relations[rel.SourceID], rel.TargetID)
+	}
+
+	views := make([]structurizrView, 0, len(r.v.rootComponentTags))
+	for _, tag := range r.v.rootComponentTags {
+		reached := make(map[string]struct{})
+		queue := make([]string, 0)
+		for _, c := range r.components {
+			if hasTag(c, tag) {
+				reached[c.ID] = struct{}{}
+				queue = append(queue, c.ID)
+			}
+		}
+
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, trgID := range relations[id] {
+				if _, ok := reached[trgID]; ok {
+					continue
+				}
+				reached[trgID] = struct{}{}
+				queue = append(queue, trgID)
+			}
+		}
+
+		if len(reached) == 0 {
+			continue
+		}
+
+		ids := make([]string, 0, len(reached))
+		for _, c := range r.components {
+			if _, ok := reached[c.ID]; ok {
+				ids = append(ids, c.ID)
+			}
+		}
+		views = append(views, structurizrView{
+			key:        tag,
+			title:      fmt.Sprintf("%s - %s", r.v.title, tag),
+			elementIDs: ids,
+		})
+	}
+
+	return views
+}
+
+func (r *structurizrRenderer) styles() []ComponentStyle {
+	styles := make([]ComponentStyle, 0, len(r.v.componentStyles))
+	for _, s := range r.v.componentStyles {
+		styles = append(styles, s)
+	}
+	sort.Slice(styles, func(i, j int) bool {
+		return styles[i].id < styles[j].id
+	})
+	return styles
+}
+
+func (r *structurizrRenderer) renderDSL() string {
+	sb := strings.Builder{}
+
+	sb.WriteString(strings.Replace(snippetStructurizrHead, paramTitle, dslText(r.v.title), -1))
+
+	for _, c := range r.components {
+		t := structurizrElementType(c.Kind)
+		if t == structurizrPerson || t == structurizrSystem {
+			sb.WriteString(buildStructurizrElement(c, t, "        "))
+		}
+	}
+
+	s := snippetStructurizrSystemHead
+	s = strings.Replace(s, paramSystemID, structurizrSystemID, -1)
+	s = strings.Replace(s, paramTitle, dslText(r.v.title), -1)
+	sb.WriteString(s)
+
+	for _, c := range r.components {
+		if structurizrElementType(c.Kind) == structurizrContainer {
+			sb.WriteString(buildStructurizrElement(c, structurizrContainer, "            "))
+		}
+	}
+
+	s = snippetStructurizrContainerHead
+	s = strings.Replace(s, paramContainerID, structurizrContainerID, -1)
+	s = strings.Replace(s, paramTitle, dslText(r.v.title), -1)
+	sb.WriteString(s)
+
+	for _, c := range r.components {
+		if structurizrElementType(c.Kind) == structurizrComponent {
+			sb.WriteString(buildStructurizrElement(c, structurizrComponent, "                "))
+		}
+	}
+
+	sb.WriteString(snippetStructurizrContainerTail)
+	sb.WriteString(snippetStructurizrSystemTail)
+
+	if len(r.relations) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, rel := range r.relations {
+		sb.WriteString(buildStructurizrRelationship(rel))
+	}
+
+	sb.WriteString(snippetStructurizrModelTail)
+
+	for _, view := range r.views() {
+		sb.WriteString(buildStructurizrView(view))
+	}
+
+	sb.WriteString(snippetStructurizrStylesHead)
+	for _, style := range r.styles() {
+		sb.WriteString(buildStructurizrElementStyle(style))
+	}
+	sb.WriteString(buildStructurizrRelationshipStyle("Relationship", r.v.lineColor))
+	for _, tag := range r.v.relationDiffTags() {
+		sb.WriteString(buildStructurizrRelationshipStyle(tag, r.v.relationLineColor(model.Relation{Tags: []string{tag}})))
+	}
+	sb.WriteString(snippetStructurizrStylesTail)
+
+	sb.WriteString(snippetStructurizrTail)
+
+	return sb.String()
+}
+
+func buildStructurizrElement(
+	c model.Component,
+	elementType string,
+	indent string,
+) string {
+	technology := ""
+	if elementType == structurizrContainer || elementType == structurizrComponent {
+		technology = fmt.Sprintf(` "%s"`, dslText(c.Technology))
+	}
+
+	s := snippetStructurizrElement
+	s = strings.Replace(s, paramIndent, indent, -1)
+	s = strings.Replace(s, paramComponentID, structurizrIdentifier(c.ID), -1)
+	s = strings.Replace(s, paramElementType, elementType, -1)
+	s = strings.Replace(s, paramComponentName, dslText(c.Name), -1)
+	s = strings.Replace(s, paramComponentDescription, dslText(c.Description), -1)
+	s = strings.Replace(s, paramComponentTechnology, technology, -1)
+	s = strings.Replace(s, paramComponentTags, dslText(strings.Join(c.Tags, ",")), -1)
+	return s
+}
+
+func buildStructurizrRelationship(
+	r model.Relation,
+) string {
+	s := snippetStructurizrRelationship
+	s = strings.Replace(s, paramComponentIDFrom, structurizrIdentifier(r.SourceID), -1)
+	s = strings.Replace(s, paramComponentIDTo, structurizrIdentifier(r.TargetID), -1)
+	s = strings.Replace(s, paramRelationLabel, dslText(structurizrDescription(r)), -1)
+	s = strings.Replace(s, paramRelationTechnology, dslText(r.Technology), -1)
+	s = strings.Replace(s, paramRelationTags, dslText(strings.Join(r.Tags, ",")), -1)
+	return s
+}
+
+func buildStructurizrView(
+	v structurizrView,
+) string {
+	ids := make([]string, len(v.elementIDs))
+	for i, id := range v.elementIDs {
+		ids[i] = structurizrIdentifier(id)
+	}
+
+	s := snippetStructurizrView
+	s = strings.Replace(s, paramContainerID, structurizrContainerID, -1)
+	s = strings.Replace(s, paramViewKey, structurizrKey(v.key), -1)
+	s = strings.Replace(s, paramTitle, dslText(v.title), -1)
+	s = strings.Replace(s, paramViewElements, strings.Join(ids, " "), -1)
+	return s
+}
+
+func buildStructurizrRelationshipStyle(
+	tag string,
+	lineColor color.Color,
+) string {
+	s := snippetStructurizrRelationshipStyle
+	s = strings.Replace(s, paramRelationTags, dslText(tag), -1)
+	s = strings.Replace(s, paramLineColor, toHex(lineColor), -1)
+	return s
+}
+
+func buildStructurizrElementStyle(
+	style ComponentStyle,
+) string {
+	s := snippetStructurizrElementStyle
+	s = strings.Replace(s, paramShapeStyle, dslText(style.id), -1)
+	s = strings.Replace(s, paramBackgroundColor, toHex(style.backgroundColor), -1)
+	s = strings.Replace(s, paramFontColor, toHex(style.fontColor), -1)
+	s = strings.Replace(s, paramBorderColor, toHex(style.borderColor), -1)
+	s = strings.Replace(s, paramShape, structurizrShape(style.shape), -1)
+	return s
+}
+
+type structurizrJSONWorkspace struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Model       structurizrJSONModel `json:"model"`
+	Views       structurizrJSONViews `json:"views"`
+}
+
+type structurizrJSONModel struct {
+	People          []structurizrJSONElement `json:"people,omitempty"`
+	SoftwareSystems []structurizrJSONElement `json:"softwareSystems"`
+}
+
+type structurizrJSONElement struct {
+	ID            string                        `json:"id"`
+	Name          string                        `json:"name"`
+	Description   string                        `json:"description,omitempty"`
+	Technology    string                        `json:"technology,omitempty"`
+	Tags          string                        `json:"tags"`
+	Relationships []structurizrJSONRelationship `json:"relationships,omitempty"`
+	Containers    []structurizrJSONElement      `json:"containers,omitempty"`
+	Components    []structurizrJSONElement      `json:"components,omitempty"`
+}
+
+type structurizrJSONRelationship struct {
+	ID            string `json:"id"`
+	SourceID      string `json:"sourceId"`
+	DestinationID string `json:"destinationId"`
+	Description   string `json:"description,omitempty"`
+	Technology    string `json:"technology,omitempty"`
+	Tags          string `json:"tags"`
+}
+
+type structurizrJSONViews struct {
+	ComponentViews []structurizrJSONView        `json:"componentViews,omitempty"`
+	Configuration  structurizrJSONConfiguration `json:"configuration"`
+}
+
+type structurizrJSONView struct {
+	Key             string                   `json:"key"`
+	Title           string                   `json:"title"`
+	ContainerID     string                   `json:"containerId"`
+	Elements        []structurizrJSONViewRef `json:"elements"`
+	Relationships   []structurizrJSONViewRef `json:"relationships"`
+	AutomaticLayout structurizrJSONLayout    `json:"automaticLayout"`
+}
+
+type structurizrJSONViewRef struct {
+	ID string `json:"id"`
+}
+
+type structurizrJSONLayout struct {
+	RankDirection string `json:"rankDirection"`
+}
+
+type structurizrJSONConfiguration struct {
+	Styles structurizrJSONStyles `json:"styles"`
+}
+
+type structurizrJSONStyles struct {
+	Elements      []structurizrJSONElementStyle      `json:"elements"`
+	Relationships []structurizrJSONRelationshipStyle `json:"relationships"`
+}
+
+type structurizrJSONElementStyle struct {
+	Tag        string `json:"tag"`
+	Background string `json:"background"`
+	Color      string `json:"color"`
+	Stroke     string `json:"stroke"`
+	Shape      string `json:"shape"`
+}
+
+type structurizrJSONRelationshipStyle struct {
+	Tag    string `json:"tag"`
+	Color  string `json:"color"`
+	Dashed bool   `json:"dashed"`
+}
+
+func (r *structurizrRenderer) renderJSON() (string, error) {
+	relationships := make(map[string][]structurizrJSONRelationship)
+	for _, rel := range r.relations {
+		relationships[rel.SourceID] = append(relationships[rel.SourceID], structurizrJSONRelationship{
+			ID:            structurizrRelationshipID(rel),
+			SourceID:      structurizrIdentifier(rel.SourceID),
+			DestinationID: structurizrIdentifier(rel.TargetID),
+			Description:   structurizrDescription(rel),
+			Technology:    rel.Technology,
+			Tags:          structurizrJSONTags("Relationship", rel.Tags),
+		})
+	}
+
+	container := structurizrJSONElement{
+		ID:   structurizrContainerID,
+		Name: r.v.title,
+		Tags: structurizrTags[structurizrContainer],
+	}
+	system := structurizrJSONElement{
+		ID:   structurizrSystemID,
+		Name: r.v.title,
+		Tags: structurizrTags[structurizrSystem],
+	}
+	m := structurizrJSONModel{}
+
+	for _, c := range r.components {
+		t := structurizrElementType(c.Kind)
+		e := structurizrJSONElement{
+			ID:            structurizrIdentifier(c.ID),
+			Name:          c.Name,
+			Description:   c.Description,
+			Tags:          structurizrJSONTags(structurizrTags[t], c.Tags),
+			Relationships: relationships[c.ID],
+		}
+
+		switch t {
+		case structurizrPerson:
+			m.People = append(m.People, e)
+		case structurizrSystem:
+			m.SoftwareSystems = append(m.SoftwareSystems, e)
+		case structurizrContainer:
+			e.Technology = c.Technology
+			system.Containers = append(system.Containers, e)
+		default:
+			e.Technology = c.Technology
+			container.Components = append(container.Components, e)
+		}
+	}
+
+	system.Containers = append(system.Containers, container)
+	m.SoftwareSystems = append(m.SoftwareSystems, system)
+
+	views := structurizrJSONViews{
+		Configuration: structurizrJSONConfiguration{
+			Styles: structurizrJSONStyles{
+				Elements: make([]structurizrJSONElementStyle, 0),
+				Relationships: []structurizrJSONRelationshipStyle{
+					{
+						Tag:    "Relationship",
+						Color:  toHex(r.v.lineColor),
+						Dashed: true,
+					},
+				},
+			},
+		},
+	}
+	for _, tag := range r.v.relationDiffTags() {
+		views.Configuration.Styles.Relationships = append(views.Configuration.Styles.Relationships, structurizrJSONRelationshipStyle{
+			Tag:    tag,
+			Color:  toHex(r.v.relationLineColor(model.Relation{Tags: []string{tag}})),
+			Dashed: true,
+		})
+	}
+
+	for _, view := range r.views() {
+		included := make(map[string]struct{}, len(view.elementIDs))
+		jsonView := structurizrJSONView{
+			Key:             structurizrKey(view.key),
+			Title:           view.title,
+			ContainerID:     structurizrContainerID,
+			Elements:        make([]structurizrJSONViewRef, 0, len(view.elementIDs)),
+			Relationships:   make([]structurizrJSONViewRef, 0),
+			AutomaticLayout: structurizrJSONLayout{RankDirection: "TopBottom"},
+		}
+		for _, id := range view.elementIDs {
+			included[id] = struct{}{}
+			jsonView.Elements = append(jsonView.Elements, structurizrJSONViewRef{ID: structurizrIdentifier(id)})
+		}
+		for _, rel := range r.relations {
+			_, src := included[rel.SourceID]
+			_, trg := included[rel.TargetID]
+			if src && trg {
+				jsonView.Relationships = append(jsonView.Relationships, structurizrJSONViewRef{ID: structurizrRelationshipID(rel)})
+			}
+		}
+		views.ComponentViews = append(views.ComponentViews, jsonView)
+	}
+
+	for _, style := range r.styles() {
+		views.Configuration.Styles.Elements = append(views.Configuration.Styles.Elements, structurizrJSONElementStyle{
+			Tag:        style.id,
+			Background: toHex(style.backgroundColor),
+			Color:      toHex(style.fontColor),
+			Stroke:     toHex(style.borderColor),
+			Shape:      structurizrShape(style.shape),
+		})
+	}
+
+	w := structurizrJSONWorkspace{
+		Name:        r.v.title,
+		Description: "This workspace has been generated with go-structurizr [https://github.com/krzysztofreczek/go-structurizr]",
+		Model:       m,
+		Views:       views,
+	}
+
+	b, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "could not marshal Structurizr workspace")
+	}
+
+	return string(b) + "\n", nil
+}
+
+// structurizrElementType maps the kind of the component to
+// the type of the Structurizr element.
+func structurizrElementType(kind string) string {
+	switch strings.ToLower(kind) {
+	case "person":
+		return structurizrPerson
+	case "system", "softwaresystem", "software system":
+		return structurizrSystem
+	case "container":
+		return structurizrContainer
+	}
+	return structurizrComponent
+}
+
+func structurizrShape(shape string) string {
+	s, ok := structurizrShapes[shape]
+	if !ok {
+		return structurizrShapes[defaultShape]
+	}
+	return s
+}
+
+// structurizrIdentifier returns an identifier of the element that is valid
+// in the Structurizr DSL.
+func structurizrIdentifier(id string) string {
+	return "c_" + invalidIDCharsRegexp.ReplaceAllString(id, "_")
+}
+
+func structurizrKey(tag string) string {
+	return invalidIDCharsRegexp.ReplaceAllString(tag, "_")
+}
+
+func structurizrRelationshipID(r model.Relation) string {
+	return structurizrIdentifier(r.SourceID) + "-" + structurizrIdentifier(r.TargetID)
+}
+
+// structurizrDescription returns the label of the relation or, if not
+// provided, the path of fields and methods leading from the source to the target.
+func structurizrDescription(r model.Relation) string {
+	if r.Label != "" {
+		return r.Label
+	}
+	return r.Path
+}
+
+func structurizrJSONTags(defaultTags string, tags []string) string {
+	return strings.Join(append([]string{defaultTags}, tags...), ",")
+}
+
+func dslText(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return s
+}
+
+func hasTag(c model.Component, tag string) bool {
+	for _, t := range c.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package view_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/color"
+	"testing"
+
+	"github.com/krzysztofreczek/go-structurizr/pkg/model"
+	"github.com/krzysztofreczek/go-structurizr/pkg/view"
+	"github.com/stretchr/testify/require"
+)
+
+func structurizrTestStructure() model.Structure {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:          "ID_1",
+			Kind:        "component",
+			Name:        "app.Handler",
+			Description: "handles requests",
+			Technology:  "Go",
+			Tags:        []string{"ROOT"},
+		},
+		"ID_2": {
+			ID:   "ID_2",
+			Kind: "component",
+			Name: "app.Repository",
+			Tags: []string{"DB"},
+		},
+		"ID_3": {
+			ID:   "ID_3",
+			Kind: "softwareSystem",
+			Name: "Payments",
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"ID_1": {
+			"ID_2": {
+				Label:      "reads",
+				Technology: "SQL",
+			},
+			"ID_3": {
+				Path: "Handler.payments",
+			},
+		},
+	}
+	return s
+}
+
+func structurizrTestView(f view.Format) view.View {
+	return view.NewView().
+		WithTitle("App").
+		WithFormat(f).
+		WithRootComponentTag("ROOT").
+		WithComponentStyle(
+			view.NewComponentStyle("DB").
+				WithBackgroundColor(color.Black).
+				WithFontColor(color.White).
+				WithBorderColor(color.Black).
+				WithShape("database").
+				Build(),
+		).
+		Build()
+}
+
+func TestNewView_structurizr_dsl(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := structurizrTestView(view.FormatStructurizrDSL)
+	err := v.RenderStructureTo(structurizrTestStructure(), &out)
+	require.NoError(t, err)
+
+	expectedContent := `/*
+ * This workspace has been generated with go-structurizr
+ * [https://github.com/krzysztofreczek/go-structurizr]
+ */
+workspace "App" {
+
+    model {
+        c_ID_3 = softwareSystem "Payments" "" ""
+        system = softwareSystem "App" {
+            container = container "App" {
+                c_ID_1 = component "app.Handler" "handles requests" "Go" "ROOT"
+                c_ID_2 = component "app.Repository" "" "" "DB"
+            }
+        }
+
+        c_ID_1 -> c_ID_2 "reads" "SQL" ""
+        c_ID_1 -> c_ID_3 "Handler.payments" "" ""
+    }
+
+    views {
+        component container "ROOT" "App - ROOT" {
+            include c_ID_1 c_ID_2 c_ID_3
+            autoLayout
+        }
+
+        styles {
+            element "DB" {
+                background #000000
+                color #ffffff
+                stroke #000000
+                shape Cylinder
+            }
+            relationship "Relationship" {
+                color #000000
+                dashed true
+            }
+        }
+    }
+}
+`
+	require.Equal(t, expectedContent, out.String())
+}
+
+func TestNewView_structurizr_json(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := structurizrTestView(view.FormatStructurizrJSON)
+	err := v.RenderStructureTo(structurizrTestStructure(), &out)
+	require.NoError(t, err)
+
+	var workspace struct {
+		Name  string `json:"name"`
+		Model struct {
+			SoftwareSystems []struct {
+				ID         string `json:"id"`
+				Name       string `json:"name"`
+				Tags       string `json:"tags"`
+				Containers []struct {
+					ID         string `json:"id"`
+					Components []struct {
+						ID            string `json:"id"`
+						Name          string `json:"name"`
+						Technology    string `json:"technology"`
+						Tags          string `json:"tags"`
+						Relationships []struct {
+							SourceID      string `json:"sourceId"`
+							DestinationID string `json:"destinationId"`
+							Description   string `json:"description"`
+							Technology    string `json:"technology"`
+						} `json:"relationships"`
+					} `json:"components"`
+				} `json:"containers"`
+			} `json:"softwareSystems"`
+		} `json:"model"`
+		Views struct {
+			ComponentViews []struct {
+				Key         string `json:"key"`
+				ContainerID string `json:"containerId"`
+				Elements    []struct {
+					ID string `json:"id"`
+				} `json:"elements"`
+			} `json:"componentViews"`
+			Configuration struct {
+				Styles struct {
+					Elements []struct {
+						Tag        string `json:"tag"`
+						Background string `json:"background"`
+						Shape      string `json:"shape"`
+					} `json:"elements"`
+				} `json:"styles"`
+			} `json:"configuration"`
+		} `json:"views"`
+	}
+	err = json.Unmarshal(out.Bytes(), &workspace)
+	require.NoError(t, err)
+
+	require.Equal(t, "App", workspace.Name)
+
+	systems := workspace.Model.SoftwareSystems
+	require.Len(t, systems, 2)
+	require.Equal(t, "c_ID_3", systems[0].ID)
+	require.Equal(t, "Element,Software System", systems[0].Tags)
+	require.Equal(t, "system", systems[1].ID)
+	require.Len(t, systems[1].Containers, 1)
+
+	components := systems[1].Containers[0].Components
+	require.Len(t, components, 2)
+	require.Equal(t, "c_ID_1", components[0].ID)
+	require.Equal(t, "Go", components[0].Technology)
+	require.Equal(t, "Element,Component,ROOT", components[0].Tags)
+	require.Len(t, components[0].Relationships, 2)
+	require.Equal(t, "c_ID_2", components[0].Relationships[0].DestinationID)
+	require.Equal(t, "reads", components[0].Relationships[0].Description)
+	require.Equal(t, "SQL", components[0].Relationships[0].Technology)
+	require.Equal(t, "Handler.payments", components[0].Relationships[1].Description)
+
+	views := workspace.Views.ComponentViews
+	require.Len(t, views, 1)
+	require.Equal(t, "ROOT", views[0].Key)
+	require.Equal(t, "container", views[0].ContainerID)
+	require.Len(t, views[0].Elements, 3)
+
+	styles := workspace.Views.Configuration.Styles.Elements
+	require.Len(t, styles, 1)
+	require.Equal(t, "DB", styles[0].Tag)
+	require.Equal(t, "#000000", styles[0].Background)
+	require.Equal(t, "Cylinder", styles[0].Shape)
+}
+
+func TestNewView_structurizr_dsl_empty(t *testing.T) {
+	out := bytes.Buffer{}
+
+	v := view.NewView().
+		WithFormat(view.FormatStructurizrDSL).
+		Build()
+	err := v.RenderStructureTo(model.NewStructure(), &out)
+	require.NoError(t, err)
+
+	require.NotContains(t, out.String(), "include")
+}
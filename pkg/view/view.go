@@ -3,6 +3,7 @@ package view
 import (
 	"image/color"
 	"io"
+	"log/slog"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
 	"github.com/krzysztofreczek/go-structurizr/pkg/yaml"
@@ -24,6 +25,10 @@ type view struct {
 	componentTags     []string
 	componentStyles   map[string]ComponentStyle
 	lineColor         color.Color
+	format            Format
+	c4Include         string
+	diffHighlight     bool
+	logger            *slog.Logger
 }
 
 func newView(
@@ -32,6 +37,10 @@ func newView(
 	componentTags []string,
 	componentStyles map[string]ComponentStyle,
 	lineColor color.Color,
+	format Format,
+	c4Include string,
+	diffHighlight bool,
+	logger *slog.Logger,
 ) View {
 	return view{
 		title:             title,
@@ -39,6 +48,10 @@ func newView(
 		componentTags:     componentTags,
 		componentStyles:   componentStyles,
 		lineColor:         lineColor,
+		format:            format,
+		c4Include:         c4Include,
+		diffHighlight:     diffHighlight,
+		logger:            newLogger(logger),
 	}
 }
 
@@ -51,6 +64,8 @@ func NewView() Builder {
 			componentTags:     make([]string, 0),
 			componentStyles:   make(map[string]ComponentStyle),
 			lineColor:         color.Black,
+			format:            FormatPlantUML,
+			c4Include:         defaultC4Include,
 		},
 	}
 }
@@ -86,6 +101,11 @@ func NewViewFromConfigFile(fileName string) (View, error) {
 // WithComponentStyle adds custom styles for components. Styles are applied to components
 // tagged with the specified style ID.
 // WithLineColor sets a custom line color.
+// WithFormat sets the format the view is rendered in. It defaults to PlantUML.
+// WithC4Include sets the path of the C4-PlantUML library included by views
+// rendered in the C4-PlantUML format. It defaults to the PlantUML standard library.
+// WithDiffHighlight highlights elements marked by `model.StructureDiff.Union`.
+// WithLogger sets the logger receiving debug messages of the rendering process.
 //
 // Build returns a default View implementation based on the provided configuration.
 // Colors default to black or white if not specified.
@@ -95,6 +115,10 @@ type Builder interface {
 	WithComponentTag(t string) Builder
 	WithComponentStyle(s ComponentStyle) Builder
 	WithLineColor(c color.Color) Builder
+	WithFormat(f Format) Builder
+	WithC4Include(path string) Builder
+	WithDiffHighlight() Builder
+	WithLogger(l *slog.Logger) Builder
 
 	Build() View
 }
@@ -144,16 +168,72 @@ func (b *builder) WithLineColor(c color.Color) Builder {
 	return b
 }
 
+// WithFormat sets the format the view is rendered in.
+//
+// The same components, relations and styles are rendered regardless
+// of the format.
+func (b *builder) WithFormat(f Format) Builder {
+	if f != "" {
+		b.format = f
+	}
+	return b
+}
+
+// WithC4Include sets the path of the C4-PlantUML library, e.g. a local
+// `./C4_Component.puml` file, included by views rendered in the
+// C4-PlantUML format.
+//
+// If not specified, `<C4/C4_Component>` of the PlantUML standard library is included.
+func (b *builder) WithC4Include(path string) Builder {
+	if path != "" {
+		b.c4Include = path
+	}
+	return b
+}
+
+// WithDiffHighlight highlights components and relations marked with the tags
+// of `model.StructureDiff.Union`: added elements are rendered in green,
+// removed in red and changed in amber.
+//
+// The highlight colors can be overridden with component styles of the
+// `model.DiffTagAdded`, `model.DiffTagRemoved` and `model.DiffTagChanged` IDs.
+func (b *builder) WithDiffHighlight() Builder {
+	b.diffHighlight = true
+	return b
+}
+
+// WithLogger sets the logger receiving debug messages of the rendering process,
+// with the id, name and tags of the rendered component attached.
+//
+// If not specified, messages are written with the standard logger only if
+// the `LOG_LEVEL` environment variable is set to `DEBUG`.
+func (b *builder) WithLogger(l *slog.Logger) Builder {
+	b.logger = l
+	return b
+}
+
 // Build returns a default View implementation based on the provided configuration.
 //
 // If not specified, all colors default to black or white.
 func (b builder) Build() View {
+	if b.diffHighlight {
+		for _, s := range diffComponentStyles {
+			if _, ok := b.componentStyles[s.id]; !ok {
+				b.componentStyles[s.id] = s
+			}
+		}
+	}
+
 	return newView(
 		b.title,
 		b.rootComponentTags,
 		b.componentTags,
 		b.componentStyles,
 		b.lineColor,
+		b.format,
+		b.c4Include,
+		b.diffHighlight,
+		b.logger,
 	)
 }
 
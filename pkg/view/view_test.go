@@ -2,7 +2,9 @@ package view_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"image/color"
+	"log/slog"
 	"testing"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/model"
@@ -122,6 +124,36 @@ func TestNewView_with_component(t *testing.T) {
 	require.Contains(t, outString, expectedContent)
 }
 
+func TestNewView_with_logger(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID:   "ID_1",
+			Kind: "component",
+			Name: "test.Component",
+			Tags: []string{"tag 1"},
+		},
+	}
+
+	logs := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	v := view.NewView().WithLogger(logger).Build()
+	err := v.RenderStructureTo(s, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	var record struct {
+		Msg  string   `json:"msg"`
+		ID   string   `json:"id"`
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	require.NoError(t, json.NewDecoder(&logs).Decode(&record))
+	require.Equal(t, "ID_1", record.ID)
+	require.Equal(t, "test.Component", record.Name)
+	require.Equal(t, []string{"tag 1"}, record.Tags)
+}
+
 func TestNewView_with_relation(t *testing.T) {
 	s := model.NewStructure()
 	s.Components = map[string]model.Component{
@@ -135,7 +167,7 @@ func TestNewView_with_relation(t *testing.T) {
 			ID: "ID_3",
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 			"ID_3": {},
@@ -161,6 +193,64 @@ ID_1 .[#000000].> ID_3 : ""
 	require.Contains(t, outString, expectedContent)
 }
 
+func TestNewView_with_relation_details(t *testing.T) {
+	s := model.NewStructure()
+	s.Components = map[string]model.Component{
+		"ID_1": {
+			ID: "ID_1",
+		},
+		"ID_2": {
+			ID: "ID_2",
+		},
+		"ID_3": {
+			ID: "ID_3",
+		},
+		"ID_4": {
+			ID: "ID_4",
+		},
+	}
+	s.Relations = map[string]map[string]model.Relation{
+		"ID_1": {
+			"ID_2": {
+				Path: "Component.field",
+				Kind: model.RelationKindField,
+			},
+			"ID_3": {
+				Label:      "reads",
+				Path:       "Component.field",
+				Technology: "SQL",
+			},
+			"ID_4": {
+				Label:      `publishes "order created"`,
+				Technology: `Kafka "orders"`,
+			},
+		},
+	}
+
+	out := bytes.Buffer{}
+
+	v := view.NewView().Build()
+	err := v.RenderStructureTo(s, &out)
+	require.NoError(t, err)
+
+	outString := out.String()
+
+	expectedContent := `
+ID_1 .[#000000].> ID_2 : "Component.field"
+`
+	require.Contains(t, outString, expectedContent)
+
+	expectedContent = `
+ID_1 .[#000000].> ID_3 : "reads\n[SQL]"
+`
+	require.Contains(t, outString, expectedContent)
+
+	expectedContent = `
+ID_1 .[#000000].> ID_4 : "publishes 'order created'\n[Kafka 'orders']"
+`
+	require.Contains(t, outString, expectedContent)
+}
+
 func TestNewView_with_custom_line_color(t *testing.T) {
 	s := model.NewStructure()
 	s.Components = map[string]model.Component{
@@ -171,7 +261,7 @@ func TestNewView_with_custom_line_color(t *testing.T) {
 			ID: "ID_2",
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 		},
@@ -269,7 +359,7 @@ func TestNewView_with_two_joined_components_of_view_tag(t *testing.T) {
 			Tags:        []string{"tag 2"},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 		},
@@ -322,7 +412,7 @@ func TestNewView_with_two_joined_components_where_one_with_no_view_tag(t *testin
 			Tags:        []string{},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 		},
@@ -411,7 +501,7 @@ func TestNewView_with_two_joined_components_of_view_root_tag(t *testing.T) {
 			Tags:        []string{},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 		},
@@ -463,7 +553,7 @@ func TestNewView_with_two_joined_components_where_one_with_no_view_root_tag(t *t
 			Tags:        []string{},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 		},
@@ -506,7 +596,7 @@ func TestNewView_with_component_with_no_connection_to_root(t *testing.T) {
 			Tags:        []string{},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{}
+	s.Relations = map[string]map[string]model.Relation{}
 
 	out := bytes.Buffer{}
 
@@ -543,7 +633,7 @@ func TestNewView_creates_grouping(t *testing.T) {
 			Tags: []string{"TAG_B"},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 			"ID_3": {},
@@ -6,11 +6,28 @@ import (
 	"log"
 
 	"github.com/krzysztofreczek/go-structurizr/pkg/yaml"
+	"github.com/pkg/errors"
 )
 
 func toView(c yaml.Config) (View, error) {
 	v := NewView().WithTitle(c.View.Title)
 
+	if c.View.Format != "" {
+		f := Format(c.View.Format)
+		if _, ok := renderers[f]; !ok {
+			return view{}, errors.Errorf("unknown view format `%s`", f)
+		}
+		v.WithFormat(f)
+	}
+
+	if c.View.C4Include != "" {
+		v.WithC4Include(c.View.C4Include)
+	}
+
+	if c.View.DiffHighlight {
+		v.WithDiffHighlight()
+	}
+
 	if c.View.LineColor != "" {
 		col, err := decodeHexColor(c.View.LineColor)
 		if err != nil {
@@ -64,7 +64,7 @@ func Test_toView(t *testing.T) {
 			Tags:        []string{"tag 2"},
 		},
 	}
-	s.Relations = map[string]map[string]struct{}{
+	s.Relations = map[string]map[string]model.Relation{
 		"ID_1": {
 			"ID_2": {},
 		},
@@ -82,3 +82,36 @@ func Test_toView(t *testing.T) {
 
 	require.Equal(t, expectedOutput, actualOutput)
 }
+
+func Test_toView_with_format(t *testing.T) {
+	yamlConfiguration := yaml.Config{
+		View: yaml.ConfigView{
+			Format: "mermaid",
+		},
+	}
+
+	v, err := toView(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, FormatMermaid, v.(view).format)
+	require.Equal(t, defaultC4Include, v.(view).c4Include)
+
+	yamlConfiguration.View.Format = "c4-plantuml"
+	yamlConfiguration.View.C4Include = "./C4_Component.puml"
+
+	v, err = toView(yamlConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, FormatC4PlantUML, v.(view).format)
+	require.Equal(t, "./C4_Component.puml", v.(view).c4Include)
+
+	yamlConfiguration.View.DiffHighlight = true
+
+	v, err = toView(yamlConfiguration)
+	require.NoError(t, err)
+	require.True(t, v.(view).diffHighlight)
+	require.Contains(t, v.(view).componentStyles, model.DiffTagAdded)
+
+	yamlConfiguration.View.Format = "unknown"
+
+	_, err = toView(yamlConfiguration)
+	require.Error(t, err)
+}
@@ -16,14 +16,45 @@ type Config struct {
 
 // ConfigConfiguration represents a YAML configuration structure.
 type ConfigConfiguration struct {
-	Packages []string `yaml:"pkgs"`
+	Packages               []string     `yaml:"pkgs"`
+	ExcludedPackages       []string     `yaml:"exclude_pkgs"`
+	ExcludedPackageRegexps []string     `yaml:"exclude_pkg_regexps"`
+	IDStrategy             string       `yaml:"id_strategy"`
+	Generics               string       `yaml:"generics"`
+	Instances              string       `yaml:"instances"`
+	Limits                 ConfigLimits `yaml:"limits"`
+}
+
+// ConfigLimits represents a YAML configuration structure for scraping limits.
+type ConfigLimits struct {
+	MaxDepth      int `yaml:"max_depth"`
+	MaxComponents int `yaml:"max_components"`
+	MaxElements   int `yaml:"max_elements"`
+	MaxRecursion  int `yaml:"max_recursion"`
 }
 
 // ConfigRule represents a YAML configuration structure for rules.
+//
+// If Ignore is set, the rule is an ignore rule and Component is not used.
 type ConfigRule struct {
 	PackageRegexps []string            `yaml:"pkg_regexps"`
 	NameRegexp     string              `yaml:"name_regexp"`
+	Match          *ConfigRuleMatcher  `yaml:"match"`
 	Component      ConfigRuleComponent `yaml:"component"`
+	Ignore         string              `yaml:"ignore"`
+}
+
+// ConfigRuleMatcher represents a YAML configuration structure for rule matchers.
+//
+// All the conditions set within a single matcher must be met.
+type ConfigRuleMatcher struct {
+	Implements string              `yaml:"implements"`
+	Kind       string              `yaml:"kind"`
+	Method     string              `yaml:"method"`
+	StructTag  string              `yaml:"struct_tag"`
+	All        []ConfigRuleMatcher `yaml:"all"`
+	Any        []ConfigRuleMatcher `yaml:"any"`
+	Not        *ConfigRuleMatcher  `yaml:"not"`
 }
 
 // ConfigRuleComponent represents a YAML configuration structure for rule components.
@@ -41,6 +72,9 @@ type ConfigView struct {
 	Styles            []ConfigViewStyle `yaml:"styles"`
 	ComponentTags     []string          `yaml:"component_tags"`
 	RootComponentTags []string          `yaml:"root_component_tags"`
+	Format            string            `yaml:"format"`
+	C4Include         string            `yaml:"c4_include"`
+	DiffHighlight     bool              `yaml:"diff_highlight"`
 }
 
 // ConfigViewStyle represents a YAML configuration structure for view styles.
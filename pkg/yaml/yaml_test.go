@@ -13,6 +13,16 @@ const (
 	testYAMLConfiguration = `
 configuration:
   pkgs: [PKG_1, PKG_2]
+  exclude_pkgs: [PKG_1/mocks]
+  exclude_pkg_regexps: [/generated$]
+  id_strategy: readable
+  generics: merge
+  instances: separate
+  limits:
+    max_depth: 10
+    max_components: 500
+    max_elements: 20
+    max_recursion: 5
 `
 
 	testYAMLRules = `
@@ -31,6 +41,19 @@ rules:
       description: Repository description
       technology: Repository technology
       tags: [TAG_3]
+  - pkg_regexps: [PKG_1]
+    name_regexp: "Logger$"
+    ignore: all
+  - pkg_regexps: [PKG_1]
+    match:
+      implements: io.Closer
+      any:
+        - kind: func
+        - struct_tag: role
+      not:
+        method: String
+    component:
+      name: Resource
 `
 
 	testYAMLViews = `
@@ -44,6 +67,9 @@ view:
       border_color: 000000ff
   component_tags: [TAG_1, TAG_2]
   root_component_tags: [TAG_3, TAG_4]
+  format: mermaid
+  c4_include: ./C4_Component.puml
+  diff_highlight: true
 `
 )
 
@@ -63,7 +89,18 @@ func TestLoadFrom(t *testing.T) {
 			source: testYAMLConfiguration,
 			expected: yaml.Config{
 				Configuration: yaml.ConfigConfiguration{
-					Packages: []string{"PKG_1", "PKG_2"},
+					Packages:               []string{"PKG_1", "PKG_2"},
+					ExcludedPackages:       []string{"PKG_1/mocks"},
+					ExcludedPackageRegexps: []string{"/generated$"},
+					IDStrategy:             "readable",
+					Generics:               "merge",
+					Instances:              "separate",
+					Limits: yaml.ConfigLimits{
+						MaxDepth:      10,
+						MaxComponents: 500,
+						MaxElements:   20,
+						MaxRecursion:  5,
+					},
 				},
 			},
 		},
@@ -92,6 +129,25 @@ func TestLoadFrom(t *testing.T) {
 							Tags:        []string{"TAG_3"},
 						},
 					},
+					{
+						PackageRegexps: []string{"PKG_1"},
+						NameRegexp:     "Logger$",
+						Ignore:         "all",
+					},
+					{
+						PackageRegexps: []string{"PKG_1"},
+						Match: &yaml.ConfigRuleMatcher{
+							Implements: "io.Closer",
+							Any: []yaml.ConfigRuleMatcher{
+								{Kind: "func"},
+								{StructTag: "role"},
+							},
+							Not: &yaml.ConfigRuleMatcher{Method: "String"},
+						},
+						Component: yaml.ConfigRuleComponent{
+							Name: "Resource",
+						},
+					},
 				},
 			},
 		},
@@ -112,6 +168,9 @@ func TestLoadFrom(t *testing.T) {
 					},
 					ComponentTags:     []string{"TAG_1", "TAG_2"},
 					RootComponentTags: []string{"TAG_3", "TAG_4"},
+					Format:            "mermaid",
+					C4Include:         "./C4_Component.puml",
+					DiffHighlight:     true,
 				},
 			},
 		},